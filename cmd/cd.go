@@ -6,16 +6,19 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/dsaiztc/dev/internal/config"
 	"github.com/dsaiztc/dev/internal/fuzzy"
+	"github.com/dsaiztc/dev/internal/history"
 	"github.com/dsaiztc/dev/internal/repos"
 	"github.com/spf13/cobra"
 )
 
 var cdCmd = &cobra.Command{
-	Use:   "cd [query]",
-	Short: "Navigate to a project directory",
-	Long:  `Without arguments, opens an interactive fuzzy finder. With a query, jumps to the best matching repo.`,
-	RunE:  runCD,
+	Use:               "cd [query]",
+	Short:             "Navigate to a project directory",
+	Long:              `Without arguments, opens an interactive fuzzy finder. With a query, jumps to the best matching repo.`,
+	ValidArgsFunction: completeRepos,
+	RunE:              runCD,
 }
 
 func init() {
@@ -23,27 +26,23 @@ func init() {
 }
 
 func runCD(cmd *cobra.Command, args []string) error {
-	homeDir, err := os.UserHomeDir()
+	baseDir, err := config.SrcRoot()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
-	}
-
-	baseDir := filepath.Join(homeDir, "src")
-
-	allRepos, err := repos.Discover(baseDir)
-	if err != nil {
-		return fmt.Errorf("could not discover repos: %w", err)
+		return err
 	}
 
+	allRepos := repos.DiscoverCached(baseDir)
 	if len(allRepos) == 0 {
 		return fmt.Errorf("no repos found under %s", baseDir)
 	}
 
+	scores := history.ScoresUnderRoot(baseDir)
+
 	var selected string
 
 	if len(args) == 0 {
-		// Interactive fuzzy finder
-		selected, err = fuzzy.Run(allRepos)
+		// Interactive fuzzy finder, most frecent repos first
+		selected, err = fuzzy.Run(repos.SortByFrecency(allRepos, scores))
 		if err != nil {
 			return err
 		}
@@ -53,7 +52,7 @@ func runCD(cmd *cobra.Command, args []string) error {
 	} else {
 		// Fuzzy match with query
 		query := strings.Join(args, " ")
-		matches := repos.FuzzyMatch(allRepos, query)
+		matches := repos.FuzzyMatch(allRepos, query, scores)
 		if len(matches) == 0 {
 			return fmt.Errorf("no repos matching %q", query)
 		}
@@ -62,6 +61,6 @@ func runCD(cmd *cobra.Command, args []string) error {
 	}
 
 	fullPath := filepath.Join(baseDir, selected)
-	fmt.Printf("cd %s\n", fullPath)
-	return nil
+	_ = history.Visit(fullPath)
+	return emitCD(os.Stdout, fullPath)
 }
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/dsaiztc/dev/internal/progress"
+	"github.com/dsaiztc/dev/internal/repos"
 	"github.com/dsaiztc/dev/internal/repourl"
 	"github.com/spf13/cobra"
 )
@@ -13,58 +21,194 @@ import (
 var cloneCmd = &cobra.Command{
 	Use:   "clone <url>",
 	Short: "Clone a git repository into ~/src/<source>/<org>/<project>",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runClone,
+	Long: `Clones a git repository into ~/src/<source>/<org>/<project> and cd's into it.
+
+With --org <source>/<org>, clones every repo of an organization, group or user
+that is not cloned yet, listing them through the forge API. Archived repos and
+forks are skipped unless --archived or --forks is given.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if org, _ := cmd.Flags().GetString("org"); org != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if org, _ := cmd.Flags().GetString("org"); org != "" {
+			return nil
+		}
+		for _, name := range orgOnlyFlags {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s requires --org", name)
+			}
+		}
+		return nil
+	},
+	RunE: runClone,
 }
 
+// orgOnlyFlags are the dev clone flags that only apply with --org.
+var orgOnlyFlags = []string{"archived", "forks", "topic", "match", "parallel", "dry-run"}
+
 func init() {
+	cloneCmd.Flags().Bool("no-hooks", false, "skip the post-clone repo hooks")
+	cloneCmd.Flags().String("org", "", "clone all repos of <source>/<org> (e.g. github.com/mycompany)")
+	cloneCmd.Flags().Bool("archived", false, "with --org, include archived repos")
+	cloneCmd.Flags().Bool("forks", false, "with --org, include forks")
+	cloneCmd.Flags().StringSlice("topic", nil, "with --org, only clone repos with one of these topics")
+	cloneCmd.Flags().String("match", "", "with --org, only clone repos whose path within the org matches this regex")
+	cloneCmd.Flags().Int("parallel", pool.DefaultWorkers(), "with --org, number of concurrent clones")
+	cloneCmd.Flags().Bool("dry-run", false, "with --org, list the repos that would be cloned")
 	rootCmd.AddCommand(cloneCmd)
 }
 
 func runClone(cmd *cobra.Command, args []string) error {
+	if org, _ := cmd.Flags().GetString("org"); org != "" {
+		return runCloneOrg(cmd, org)
+	}
+
 	parsed, err := repourl.Parse(args[0])
 	if err != nil {
 		return fmt.Errorf("invalid repository URL: %w", err)
 	}
 
-	homeDir, err := os.UserHomeDir()
+	srcRoot, err := config.SrcRoot()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
+		return err
 	}
 
-	targetDir := filepath.Join(homeDir, "src", parsed.FullPath())
+	targetDir := filepath.Join(srcRoot, parsed.FullPath())
 
 	// Check if target already exists
-	if info, err := os.Stat(targetDir); err == nil && info.IsDir() {
-		gitDir := filepath.Join(targetDir, ".git")
-		if _, err := os.Stat(gitDir); err == nil {
-			fmt.Fprintf(os.Stderr, "already cloned at %s\n", targetDir)
-			fmt.Println(targetDir)
-			return nil
-		}
-		return fmt.Errorf("directory %s already exists but is not a git repository", targetDir)
+	if exists, err := checkCloneTarget(targetDir); err != nil {
+		return err
+	} else if exists {
+		fmt.Fprintf(os.Stderr, "already cloned at %s\n", targetDir)
+		return emitCD(os.Stdout, targetDir)
 	}
 
-	// Create parent directory
+	fmt.Fprintf(os.Stderr, "cloning into %s\n", targetDir)
+	noHooks, _ := cmd.Flags().GetBool("no-hooks")
+	if err := cloneInto(args[0], "", parsed, targetDir, noHooks, os.Stdin, os.Stderr, os.Stderr); err != nil {
+		return err
+	}
+
+	if err := repos.UpdateIndex(srcRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not update repo index: %v\n", err)
+	}
+
+	// Ask the shell wrapper to cd into the new clone
+	return emitCD(os.Stdout, targetDir)
+}
+
+// checkCloneTarget reports whether dir already holds a clone. It fails if
+// dir exists but is not a git repository.
+func checkCloneTarget(dir string) (bool, error) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return false, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return false, fmt.Errorf("directory %s already exists but is not a git repository", dir)
+	}
+	return true, nil
+}
+
+// cloneInto clones url into targetDir, creating its parent directories, then
+// applies the matching identity profile and, unless noHooks, runs the
+// post-clone hooks. branch, if set, is checked out instead of the remote's
+// default branch. git output goes to out, and identity and hook output to
+// hookOut. Without stdin git may not prompt for credentials. A failed clone
+// leaves nothing behind.
+func cloneInto(url, branch string, rp repourl.RepoPath, targetDir string, noHooks bool, stdin io.Reader, out, hookOut io.Writer) error {
 	parentDir := filepath.Dir(targetDir)
 	if err := os.MkdirAll(parentDir, 0o755); err != nil {
 		return fmt.Errorf("could not create directory %s: %w", parentDir, err)
 	}
 
-	// Run git clone
-	gitCmd := exec.Command("git", "clone", args[0], targetDir)
-	gitCmd.Stdin = os.Stdin
-	gitCmd.Stdout = os.Stderr
-	gitCmd.Stderr = os.Stderr
-
-	fmt.Fprintf(os.Stderr, "cloning into %s\n", targetDir)
+	cloneArgs := []string{"clone"}
+	if branch != "" {
+		cloneArgs = append(cloneArgs, "--branch", branch)
+	}
+	gitCmd := exec.Command("git", append(cloneArgs, url, targetDir)...)
+	gitCmd.Stdin = stdin
+	gitCmd.Stdout = out
+	gitCmd.Stderr = out
+	if stdin == nil {
+		gitCmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	}
 	if err := gitCmd.Run(); err != nil {
 		// Clean up partial directory on failure
 		os.RemoveAll(targetDir)
 		return fmt.Errorf("git clone failed: %w", err)
 	}
 
-	// Print target path to stdout (for shell wrapper to eval)
-	fmt.Println(targetDir)
+	applyIdentity(rp, targetDir, hookOut)
+	if !noHooks {
+		runRepoHooks(hooks.PostClone, rp, targetDir, hookOut)
+	}
 	return nil
 }
+
+// pendingClone is a repo to be cloned by cloneAll.
+type pendingClone struct {
+	rp     repourl.RepoPath
+	url    string
+	branch string // checked out instead of the remote's default branch, if set
+	dir    string
+}
+
+// cloneAll clones todo without prompting, running at most workers clones at
+// once and reporting progress on stderr. The output of each clone is kept
+// apart so concurrent clones don't interleave: git's is included in its
+// failure description, and that of identity and hooks (e.g. warnings) is
+// printed per repo once all clones are done. It returns the clones that
+// succeeded and the failures.
+func cloneAll(todo []pendingClone, workers int, noHooks bool, stderr io.Writer) ([]pendingClone, []string) {
+	errs := make([]error, len(todo))
+	outputs := make([]bytes.Buffer, len(todo))
+	hookOutputs := make([]bytes.Buffer, len(todo))
+	tracker := progress.Start(stderr, "cloning", len(todo))
+	pool.Each(todo, workers, func(i int, c pendingClone) {
+		tracker.Begin(c.rp.FullPath())
+		errs[i] = cloneInto(c.url, c.branch, c.rp, c.dir, noHooks, nil, &outputs[i], &hookOutputs[i])
+		if errs[i] != nil {
+			tracker.Done(c.rp.FullPath(), "failed")
+		} else {
+			tracker.Done(c.rp.FullPath(), "cloned")
+		}
+	})
+	tracker.Stop()
+
+	var cloned []pendingClone
+	var failures []string
+	for i, c := range todo {
+		if out := strings.TrimSpace(hookOutputs[i].String()); out != "" {
+			fmt.Fprintf(stderr, "%s:\n    %s\n", c.rp.FullPath(), strings.ReplaceAll(out, "\n", "\n    "))
+		}
+		if errs[i] == nil {
+			cloned = append(cloned, c)
+			continue
+		}
+		msg := fmt.Sprintf("%s: %v", c.rp.FullPath(), errs[i])
+		if out := strings.TrimSpace(outputs[i].String()); out != "" {
+			msg += "\n    " + strings.ReplaceAll(out, "\n", "\n    ")
+		}
+		failures = append(failures, msg)
+	}
+	return cloned, failures
+}
+
+// summarizesFailures marks cmd as a bulk command that summarizes the repos it
+// failed on. Cobra then skips printing the usage on error, which would only
+// bury the summary.
+func summarizesFailures(cmd *cobra.Command) {
+	cmd.SilenceUsage = true
+}
+
+// printFailures lists failure descriptions under a "failed:" heading.
+func printFailures(w io.Writer, failures []string) {
+	fmt.Fprintf(w, "\nfailed:\n")
+	for _, f := range failures {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/forge"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/spf13/cobra"
+)
+
+// orgFilter selects which of an org's repos dev clone --org clones.
+type orgFilter struct {
+	archived bool           // include archived repos
+	forks    bool           // include forks
+	topics   []string       // if set, repos must have at least one of these topics
+	match    *regexp.Regexp // if set, matched against the repo path relative to the org
+}
+
+func (f orgFilter) matches(org string, r forge.Repo) bool {
+	if r.Archived && !f.archived || r.Fork && !f.forks {
+		return false
+	}
+	if len(f.topics) > 0 && !slices.ContainsFunc(f.topics, func(t string) bool { return slices.Contains(r.Topics, t) }) {
+		return false
+	}
+	if f.match != nil {
+		rel := r.Path
+		if len(rel) > len(org) && strings.EqualFold(rel[:len(org)+1], org+"/") {
+			rel = rel[len(org)+1:]
+		}
+		return f.match.MatchString(rel)
+	}
+	return true
+}
+
+// cloneOrg clones the repos of org on source that pass filter and are not
+// cloned yet, running at most workers clones at once. Progress and the final
+// report go to stderr; with dryRun the repos that would be cloned are listed
+// there instead. Nothing goes to stdout, which the shell wrapper captures for
+// dev clone. It fails if any clone failed.
+func cloneOrg(ctx context.Context, client forge.Client, protocol, source, org, srcRoot string, filter orgFilter, workers int, noHooks, dryRun bool, stderr io.Writer) error {
+	list, err := client.ListRepos(ctx, org)
+	if err != nil {
+		return err
+	}
+
+	var todo []pendingClone
+	var matched, present int
+	var failures []string
+	for _, r := range list {
+		if !filter.matches(org, r) {
+			continue
+		}
+		matched++
+		rp := repourl.RepoPath{Source: source, Org: path.Dir(r.Path), Project: path.Base(r.Path)}
+		dir := filepath.Join(srcRoot, rp.FullPath())
+		if exists, err := checkCloneTarget(dir); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", rp.FullPath(), err))
+			continue
+		} else if exists {
+			present++
+			continue
+		}
+		todo = append(todo, pendingClone{rp: rp, url: r.CloneURL(protocol), dir: dir})
+	}
+	fmt.Fprintf(stderr, "%s/%s: %d repos, %d matching, %d already cloned\n", source, org, len(list), matched, present)
+
+	if dryRun {
+		for _, c := range todo {
+			fmt.Fprintln(stderr, c.rp.FullPath())
+		}
+		return nil
+	}
+
+	attempted := len(todo) + len(failures)
+	cloned, cloneFailures := cloneAll(todo, workers, noHooks, stderr)
+	failures = append(failures, cloneFailures...)
+
+	if len(cloned) > 0 {
+		if err := repos.UpdateIndex(srcRoot); err != nil {
+			fmt.Fprintf(stderr, "warning: could not update repo index: %v\n", err)
+		}
+	}
+	fmt.Fprintf(stderr, "cloned %d repos\n", len(cloned))
+	if len(failures) > 0 {
+		printFailures(stderr, failures)
+		return fmt.Errorf("could not clone %d of %d repos", len(failures), attempted)
+	}
+	return nil
+}
+
+// runCloneOrg implements dev clone --org <source>/<org>.
+func runCloneOrg(cmd *cobra.Command, target string) error {
+	source, org, ok := strings.Cut(strings.Trim(target, "/"), "/")
+	if !ok || org == "" {
+		return fmt.Errorf("--org must be <source>/<org> (e.g. github.com/mycompany), got %q", target)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("could not load config: %w", err)
+		}
+		cfg = &config.Config{}
+	}
+	settings, err := forge.Resolve(source, cfg)
+	if err != nil {
+		return err
+	}
+
+	filter := orgFilter{}
+	filter.archived, _ = cmd.Flags().GetBool("archived")
+	filter.forks, _ = cmd.Flags().GetBool("forks")
+	filter.topics, _ = cmd.Flags().GetStringSlice("topic")
+	if pattern, _ := cmd.Flags().GetString("match"); pattern != "" {
+		filter.match, err = regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid --match pattern: %w", err)
+		}
+	}
+	workers, _ := cmd.Flags().GetInt("parallel")
+	noHooks, _ := cmd.Flags().GetBool("no-hooks")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	srcRoot, err := config.SrcRoot()
+	if err != nil {
+		return err
+	}
+	return cloneOrg(cmd.Context(), forge.New(settings), settings.Protocol, source, org, srcRoot, filter, workers, noHooks, dryRun, os.Stderr)
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/forge"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/dsaiztc/dev/internal/shell"
+)
+
+func TestOrgFilter(t *testing.T) {
+	repos := map[string]forge.Repo{
+		"plain":    {Path: "mycompany/api"},
+		"archived": {Path: "mycompany/old", Archived: true},
+		"fork":     {Path: "mycompany/fork", Fork: true},
+		"topic":    {Path: "mycompany/svc", Topics: []string{"go", "service"}},
+		"subgroup": {Path: "mycompany/platform/infra"},
+	}
+	tests := []struct {
+		name   string
+		filter orgFilter
+		want   []string
+	}{
+		{"default", orgFilter{}, []string{"plain", "topic", "subgroup"}},
+		{"archived and forks", orgFilter{archived: true, forks: true}, []string{"plain", "archived", "fork", "topic", "subgroup"}},
+		{"topic", orgFilter{topics: []string{"service", "rust"}}, []string{"topic"}},
+		{"match", orgFilter{match: regexp.MustCompile(`^(api|platform/)`)}, []string{"plain", "subgroup"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, key := range []string{"plain", "archived", "fork", "topic", "subgroup"} {
+			if tt.filter.matches("mycompany", repos[key]) {
+				got = append(got, key)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: matched %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCloneOrg(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	remotes := t.TempDir()
+	for _, name := range []string{"api", "infra", "old"} {
+		if out, err := exec.Command("git", "init", "-q", "--bare", filepath.Join(remotes, name+".git")).CombinedOutput(); err != nil {
+			t.Fatalf("git init: %v\n%s", err, out)
+		}
+	}
+	srcRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(srcRoot, "github.com", "mycompany", "web", ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeForge{repos: []forge.Repo{
+		{Path: "mycompany/api", SSHURL: filepath.Join(remotes, "api.git")},
+		{Path: "mycompany/platform/infra", SSHURL: filepath.Join(remotes, "infra.git")},
+		{Path: "mycompany/old", SSHURL: filepath.Join(remotes, "old.git"), Archived: true},
+		{Path: "mycompany/web", SSHURL: filepath.Join(remotes, "web.git")},
+		{Path: "mycompany/gone", SSHURL: filepath.Join(remotes, "gone.git")},
+	}}
+
+	// A dry run only lists what would be cloned
+	var stderr bytes.Buffer
+	if err := cloneOrg(context.Background(), client, "", "github.com", "mycompany", srcRoot, orgFilter{}, 2, true, true, &stderr); err != nil {
+		t.Fatalf("cloneOrg dry run: %v", err)
+	}
+	want := "github.com/mycompany/api\ngithub.com/mycompany/platform/infra\ngithub.com/mycompany/gone\n"
+	if !strings.HasSuffix(stderr.String(), "already cloned\n"+want) {
+		t.Errorf("dry run stderr = %q, want it to end with %q", stderr.String(), want)
+	}
+	if _, err := os.Stat(filepath.Join(srcRoot, "github.com", "mycompany", "api")); !os.IsNotExist(err) {
+		t.Error("dry run cloned a repo")
+	}
+
+	stderr.Reset()
+	err := cloneOrg(context.Background(), client, "", "github.com", "mycompany", srcRoot, orgFilter{}, 2, true, false, &stderr)
+	if err == nil || err.Error() != "could not clone 1 of 3 repos" {
+		t.Errorf("cloneOrg error = %v, want one failure", err)
+	}
+
+	for _, rel := range []string{"mycompany/api", "mycompany/platform/infra"} {
+		if _, err := os.Stat(filepath.Join(srcRoot, "github.com", rel, ".git")); err != nil {
+			t.Errorf("%s was not cloned: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"mycompany/old", "mycompany/gone"} {
+		if _, err := os.Stat(filepath.Join(srcRoot, "github.com", rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist", rel)
+		}
+	}
+
+	out := stderr.String()
+	for _, s := range []string{
+		"github.com/mycompany: 5 repos, 4 matching, 1 already cloned",
+		"/3] cloned github.com/mycompany/api",
+		"[3/3] ",
+		"cloned 2 repos",
+		"failed:\n  github.com/mycompany/gone: git clone failed",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("stderr missing %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestCloneAll_ReportsHookWarnings(t *testing.T) {
+	remotes := setupRemotes(t)
+	initUpstream(t, filepath.Join(remotes, "me", "api.git"))
+	cfg := &config.Config{RepoHooks: []config.RepoHook{
+		{Match: "example.com/me/*", Hooks: config.Hooks{hooks.PostClone: {"echo bootstrapping; exit 1"}}},
+	}}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+
+	srcRoot := t.TempDir()
+	rp := repourl.RepoPath{Source: "example.com", Org: "me", Project: "api"}
+	todo := []pendingClone{{rp: rp, url: "https://example.com/me/api.git", dir: filepath.Join(srcRoot, rp.FullPath())}}
+	var stderr bytes.Buffer
+	cloned, failures := cloneAll(todo, 1, false, &stderr)
+
+	if len(cloned) != 1 || len(failures) != 0 {
+		t.Fatalf("cloned %d, failures %v; want the clone to succeed despite the hook", len(cloned), failures)
+	}
+	got := stderr.String()
+	if !strings.Contains(got, "example.com/me/api:\n") || !strings.Contains(got, "    bootstrapping\n") || !strings.Contains(got, "warning: post-clone hook failed") {
+		t.Errorf("stderr = %q, want the hook output and warning under the repo", got)
+	}
+	if strings.Contains(got, "Cloning into") {
+		t.Errorf("stderr = %q, want git output of successful clones left out", got)
+	}
+}
+
+func TestCloneOrg_DryRunThroughWrapper(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			io.WriteString(w, `{"login":"me"}`)
+		case "/orgs/mycompany/repos":
+			io.WriteString(w, `[{"full_name":"mycompany/api"},{"full_name":"mycompany/web"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	setupRemotes(t)
+	t.Setenv("DEV_SRC_ROOT", t.TempDir())
+	cfg := &config.Config{Forges: map[string]config.ForgeConfig{
+		"git.example.com": {Type: forge.GitHub, APIURL: srv.URL, Token: "secret"},
+	}}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+	devOnPath(t)
+
+	// The wrapper captures stdout for dev clone, so the list must not go there
+	out, err := exec.Command(bash, "-c", shell.WrapperFunc()+"\ndev clone --org git.example.com/mycompany --dry-run 2>&1").CombinedOutput()
+	if err != nil {
+		t.Fatalf("dev clone --org --dry-run: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "git.example.com/mycompany/api\ngit.example.com/mycompany/web\n") {
+		t.Errorf("output through the wrapper = %q, want the repos that would be cloned", out)
+	}
+}
+
+func TestClone_OrgOnlyFlagsNeedOrg(t *testing.T) {
+	setupRemotes(t)
+	srcRoot := t.TempDir()
+	t.Setenv("DEV_SRC_ROOT", srcRoot)
+	devOnPath(t)
+
+	out, err := exec.Command("dev", "clone", "https://example.com/me/api.git", "--dry-run").CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "--dry-run requires --org") {
+		t.Errorf("dev clone <url> --dry-run = %v\n%s; want an error", err, out)
+	}
+	if entries, _ := os.ReadDir(srcRoot); len(entries) != 0 {
+		t.Error("dev clone <url> --dry-run cloned the repo")
+	}
+}
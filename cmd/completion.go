@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"path"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish>",
+	Short: "Print the shell completion script",
+	Long: `Prints a completion script for dev to stdout. The script completes repo
+paths for cd and loc, worktree branches for wkt cd and wkt rm, and local and
+remote branches for wkt new.
+
+Load it after the wrapper from dev init, so completion applies to the dev
+function as well as the binary:
+
+  bash: source <(dev completion bash)             in ~/.bashrc
+  zsh:  source <(dev completion zsh)              in ~/.zshrc, after compinit
+  fish: dev completion fish | source              in ~/.config/fish/config.fish`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE:      runCompletion,
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(out, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(out)
+	case "fish":
+		return rootCmd.GenFishCompletion(out, true)
+	}
+	return cmd.Help()
+}
+
+// completeRepos offers repo paths under the source root for cd and loc.
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	baseDir, err := config.SrcRoot()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return repoCandidates(repos.DiscoverCached(baseDir), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// repoCandidates returns the repo paths starting with toComplete. Since cd and
+// loc take fuzzy queries, a bare project name also completes, described by its
+// full path.
+func repoCandidates(allRepos []string, toComplete string) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	for _, r := range allRepos {
+		if strings.HasPrefix(r, toComplete) {
+			candidates = append(candidates, r)
+			continue
+		}
+		if strings.Contains(toComplete, "/") {
+			continue
+		}
+		name := path.Base(r)
+		if strings.HasPrefix(name, toComplete) && !seen[name] {
+			seen[name] = true
+			candidates = append(candidates, name+"\t"+r)
+		}
+	}
+	return candidates
+}
+
+// completeWorktreeBranches offers the branches of the current repo's
+// worktrees, optionally including the main worktree.
+func completeWorktreeBranches(includeMain bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		repoInfo, err := worktree.DetectCurrentRepo()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		worktrees, err := worktree.ListWorktrees(repoInfo)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var branches []string
+		for _, wt := range worktrees {
+			if wt.IsMain && !includeMain {
+				continue
+			}
+			if wt.Branch != "" && strings.HasPrefix(wt.Branch, toComplete) {
+				branches = append(branches, wt.Branch+"\t"+wt.Path)
+			}
+		}
+		return branches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeBranches offers local and remote branch names of the current repo.
+func completeBranches(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repoInfo, err := worktree.DetectCurrentRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	all, err := worktree.ListBranches(repoInfo)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var branches []string
+	for _, b := range all {
+		if strings.HasPrefix(b, toComplete) {
+			branches = append(branches, b)
+		}
+	}
+	return branches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRefs offers local and remote-tracking branches (e.g. origin/x) for
+// flags that take a ref.
+func completeRefs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	repoInfo, err := worktree.DetectCurrentRepo()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	all, err := worktree.ListRefs(repoInfo)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var refs []string
+	for _, r := range all {
+		if strings.HasPrefix(r, toComplete) {
+			refs = append(refs, r)
+		}
+	}
+	return refs, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepoCandidates(t *testing.T) {
+	allRepos := []string{
+		"github.com/apache/kafka",
+		"github.com/dsaiztc/dev",
+		"github.com/dsaiztc/dotfiles",
+		"gitlab.com/group/sub/dev",
+	}
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"github.com/ds", []string{"github.com/dsaiztc/dev", "github.com/dsaiztc/dotfiles"}},
+		{"kaf", []string{"kafka\tgithub.com/apache/kafka"}},
+		// Same project name in two orgs is offered once
+		{"de", []string{"dev\tgithub.com/dsaiztc/dev"}},
+		{"gitlab.com/x", nil},
+	}
+
+	for _, tt := range tests {
+		got := repoCandidates(allRepos, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("repoCandidates(%q) = %q, want %q", tt.toComplete, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionCmd(t *testing.T) {
+	for _, sh := range []string{"bash", "zsh", "fish"} {
+		var buf bytes.Buffer
+		completionCmd.SetOut(&buf)
+		if err := runCompletion(completionCmd, []string{sh}); err != nil {
+			t.Fatalf("completion %s: %v", sh, err)
+		}
+		if !strings.Contains(buf.String(), "__complete") {
+			t.Errorf("completion %s script does not request dynamic completions", sh)
+		}
+	}
+	completionCmd.SetOut(nil)
+}
+
+func TestCompletionCmd_Registered(t *testing.T) {
+	var count int
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "completion" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one completion command, found %d", count)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/shell"
+)
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// emitDirective writes a single directive line for the shell wrapper to w
+// (normally stdout). value is quoted with shell.Quote; key, when non-empty,
+// is written unquoted before it and must be a valid variable name.
+func emitDirective(w io.Writer, directive, key, value string) error {
+	if strings.ContainsAny(value, "\n\r") {
+		return fmt.Errorf("cannot pass %q to the shell wrapper: contains a newline", value)
+	}
+	line := directive
+	if key != "" {
+		if !envNameRe.MatchString(key) {
+			return fmt.Errorf("invalid environment variable name %q", key)
+		}
+		line += " " + key
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", line, shell.Quote(value))
+	return err
+}
+
+// emitCD asks the shell wrapper to change directory to path.
+func emitCD(w io.Writer, path string) error {
+	return emitDirective(w, shell.DirectiveCD, "", path)
+}
+
+// emitSetenv asks the shell wrapper to export name=value.
+func emitSetenv(w io.Writer, name, value string) error {
+	return emitDirective(w, shell.DirectiveSetenv, name, value)
+}
+
+// emitSource asks the shell wrapper to source the file at path.
+func emitSource(w io.Writer, path string) error {
+	return emitDirective(w, shell.DirectiveSource, "", path)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEmitCD(t *testing.T) {
+	var buf bytes.Buffer
+	if err := emitCD(&buf, "/src/github.com/acme/it's here"); err != nil {
+		t.Fatalf("emitCD: %v", err)
+	}
+	want := "__DEV_CD__ '/src/github.com/acme/it'\\''s here'\n"
+	if buf.String() != want {
+		t.Errorf("emitCD wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEmitSetenv(t *testing.T) {
+	var buf bytes.Buffer
+	if err := emitSetenv(&buf, "DEV_REPO", "github.com/acme/api"); err != nil {
+		t.Fatalf("emitSetenv: %v", err)
+	}
+	want := "__DEV_SETENV__ DEV_REPO 'github.com/acme/api'\n"
+	if buf.String() != want {
+		t.Errorf("emitSetenv wrote %q, want %q", buf.String(), want)
+	}
+
+	if err := emitSetenv(&buf, "BAD NAME", "x"); err == nil {
+		t.Error("expected error for invalid variable name")
+	}
+}
+
+func TestEmitSource(t *testing.T) {
+	var buf bytes.Buffer
+	if err := emitSource(&buf, "/tmp/env.sh"); err != nil {
+		t.Fatalf("emitSource: %v", err)
+	}
+	want := "__DEV_SOURCE__ '/tmp/env.sh'\n"
+	if buf.String() != want {
+		t.Errorf("emitSource wrote %q, want %q", buf.String(), want)
+	}
+}
+
+func TestEmitDirective_RejectsNewlines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := emitCD(&buf, "/tmp/evil\n__DEV_SOURCE__ '/tmp/x'"); err == nil {
+		t.Error("expected error for path containing a newline")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written, got %q", buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in many repos",
+	Long: `Runs a command with each selected repo as its working directory, in parallel.
+Every line of output is prefixed with the repo's source/org/repo path; stdout
+and stderr are kept apart. DEV_REPO and DEV_REPO_PATH are set for the command.
+
+Repos are selected with --filter globs over source/org/repo (a glob also matches
+everything below it, e.g. github.com/mycompany), a fuzzy --query, or a --group
+from the config; without any, the command runs in every repo. The command is
+run directly, so use sh -c for pipes and other shell syntax.`,
+	Example: `  dev exec --filter github.com/mycompany -- go mod tidy
+  dev exec --group backend --parallel 1 -- git log --oneline --since=1.week
+  dev exec -q api -- sh -c 'git status --short | wc -l'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runExec,
+}
+
+func init() {
+	addSelectionFlags(execCmd)
+	execCmd.Flags().Int("parallel", pool.DefaultWorkers(), "number of repos the command runs in concurrently")
+	// Everything after the command name belongs to the command
+	execCmd.Flags().SetInterspersed(false)
+	summarizesFailures(execCmd)
+	rootCmd.AddCommand(execCmd)
+}
+
+// execResult is the outcome of running the command in one repo.
+type execResult struct {
+	repo string
+	err  error
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	srcRoot, repoPaths, err := selectedRepos(cmd)
+	if err != nil {
+		return err
+	}
+	if len(repoPaths) == 0 {
+		return fmt.Errorf("no repos match the selection")
+	}
+
+	workers, _ := cmd.Flags().GetInt("parallel")
+	results := execInRepos(srcRoot, repoPaths, args, workers, os.Stdout, os.Stderr)
+	return summarizeExec(os.Stderr, results)
+}
+
+// execInRepos runs argv in each of repoPaths (relative to srcRoot), at most
+// workers at a time, writing its output line by line to stdout and stderr
+// with the repo path as prefix.
+func execInRepos(srcRoot string, repoPaths, argv []string, workers int, stdout, stderr io.Writer) []execResult {
+	var mu sync.Mutex
+	results := make([]execResult, len(repoPaths))
+	pool.Each(repoPaths, workers, func(i int, rel string) {
+		rel = filepath.ToSlash(rel)
+		dir := filepath.Join(srcRoot, rel)
+		prefix := rel + ": "
+		outW := &lineWriter{mu: &mu, w: stdout, prefix: prefix}
+		errW := &lineWriter{mu: &mu, w: stderr, prefix: prefix}
+
+		c := exec.Command(argv[0], argv[1:]...)
+		c.Dir = dir
+		c.Env = append(os.Environ(), hooks.Environ(map[string]string{"DEV_REPO": rel, "DEV_REPO_PATH": dir})...)
+		c.Stdout = outW
+		c.Stderr = errW
+		err := c.Run()
+		outW.Flush()
+		errW.Flush()
+		results[i] = execResult{repo: rel, err: err}
+	})
+	return results
+}
+
+// summarizeExec lists the repos the command failed in. It fails if there
+// are any.
+func summarizeExec(w io.Writer, results []execResult) error {
+	var failed []execResult
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(w, "\nfailed in %d of %d repos:\n", len(failed), len(results))
+	for _, r := range failed {
+		fmt.Fprintf(w, "  %s: %v\n", r.repo, r.err)
+	}
+	return fmt.Errorf("command failed in %d of %d repos", len(failed), len(results))
+}
+
+// lineWriter writes complete lines to w, each preceded by prefix. A partial
+// line is held back until it is completed or flushed. Writers sharing mu
+// never interleave within a line.
+type lineWriter struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := l.emit(l.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		l.buf = l.buf[i+1:]
+	}
+}
+
+// Flush writes out a trailing partial line, terminating it.
+func (l *lineWriter) Flush() error {
+	if len(l.buf) == 0 {
+		return nil
+	}
+	line := append(l.buf, '\n')
+	l.buf = nil
+	return l.emit(line)
+}
+
+func (l *lineWriter) emit(line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := io.WriteString(l.w, l.prefix+string(line))
+	return err
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	var mu sync.Mutex
+	var buf bytes.Buffer
+	w := &lineWriter{mu: &mu, w: &buf, prefix: "repo: "}
+
+	w.Write([]byte("one\ntw"))
+	w.Write([]byte("o\nthr"))
+	if got := buf.String(); got != "repo: one\nrepo: two\n" {
+		t.Errorf("before flush = %q", got)
+	}
+	w.Flush()
+	if got := buf.String(); got != "repo: one\nrepo: two\nrepo: thr\n" {
+		t.Errorf("after flush = %q", got)
+	}
+	w.Flush()
+	if strings.Count(buf.String(), "\n") != 3 {
+		t.Errorf("a second flush wrote again: %q", buf.String())
+	}
+}
+
+func TestExecInRepos(t *testing.T) {
+	srcRoot := t.TempDir()
+	repoPaths := []string{"github.com/me/api", "github.com/me/web", "github.com/me/broken"}
+	for _, rel := range repoPaths {
+		if err := os.MkdirAll(filepath.Join(srcRoot, rel), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(srcRoot, "github.com/me/broken", "fail"), nil, 0o644)
+
+	var stdout, stderr bytes.Buffer
+	script := `echo "$DEV_REPO"; basename "$PWD"; echo warn >&2; test ! -e fail`
+	results := execInRepos(srcRoot, repoPaths, []string{"sh", "-c", script}, 2, &stdout, &stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	sort.Strings(lines)
+	want := []string{
+		"github.com/me/api: api",
+		"github.com/me/api: github.com/me/api",
+		"github.com/me/broken: broken",
+		"github.com/me/broken: github.com/me/broken",
+		"github.com/me/web: github.com/me/web",
+		"github.com/me/web: web",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("stdout lines =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+	if strings.Count(stderr.String(), ": warn\n") != 3 {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	var summary bytes.Buffer
+	err := summarizeExec(&summary, results)
+	if err == nil || err.Error() != "command failed in 1 of 3 repos" {
+		t.Errorf("error = %v", err)
+	}
+	if want := "\nfailed in 1 of 3 repos:\n  github.com/me/broken: exit status 1\n"; summary.String() != want {
+		t.Errorf("summary = %q, want %q", summary.String(), want)
+	}
+
+	summary.Reset()
+	if err := summarizeExec(&summary, results[:2]); err != nil || summary.Len() != 0 {
+		t.Errorf("summarizeExec without failures = %v, %q", err, summary.String())
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/manifest"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a manifest of the repos under the source root",
+	Long: `Writes a manifest listing the origin URL of every repo under the source root,
+to stdout or to the file given with --output. The manifest is JSON, or YAML
+when --output ends in .yaml or .yml. Repos without an origin remote are
+skipped. Restore it elsewhere with dev restore.`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "", "write the manifest to this file instead of stdout (e.g. "+manifest.DefaultFile+")")
+	exportCmd.Flags().Bool("worktrees", false, "include the branches of linked worktrees")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	srcRoot, err := config.SrcRoot()
+	if err != nil {
+		return err
+	}
+	repoPaths, err := repos.Discover(srcRoot)
+	if err != nil {
+		return fmt.Errorf("could not discover repos: %w", err)
+	}
+
+	withWorktrees, _ := cmd.Flags().GetBool("worktrees")
+	m := buildManifest(srcRoot, repoPaths, withWorktrees, os.Stderr)
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return m.Write(os.Stdout)
+	}
+	if err := manifest.CheckPath(output); err != nil {
+		return err
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("could not create manifest: %w", err)
+	}
+	write := m.Write
+	if manifest.IsYAML(output) {
+		write = m.WriteYAML
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "exported %d repos to %s\n", len(m.Repos), output)
+	return nil
+}
+
+// buildManifest lists the origin URL of each of repoPaths (relative to
+// srcRoot) and, with withWorktrees, the branches of its linked worktrees.
+// Repos without an origin are skipped with a warning on stderr.
+func buildManifest(srcRoot string, repoPaths []string, withWorktrees bool, stderr io.Writer) *manifest.Manifest {
+	m := &manifest.Manifest{Repos: []manifest.Repo{}}
+	for _, rel := range repoPaths {
+		dir := filepath.Join(srcRoot, rel)
+		url, err := manifest.OriginURL(dir)
+		if err != nil {
+			fmt.Fprintf(stderr, "skipping %s: %v\n", rel, err)
+			continue
+		}
+		repo := manifest.Repo{URL: url}
+
+		if withWorktrees {
+			wts, err := worktree.ListWorktrees(&worktree.RepoInfo{MainPath: dir})
+			if err != nil {
+				fmt.Fprintf(stderr, "warning: could not list worktrees of %s: %v\n", rel, err)
+			}
+			for _, wt := range wts {
+				if !wt.IsMain && wt.Branch != "" {
+					repo.Worktrees = append(repo.Worktrees, wt.Branch)
+				}
+			}
+		}
+		m.Repos = append(m.Repos, repo)
+	}
+	return m
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/fuzzy"
+	"github.com/dsaiztc/dev/internal/history"
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/spf13/cobra"
+)
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern> [-- <pathspec>...]",
+	Short: "Search the code of many repos",
+	Long: `Searches the tracked files of each selected repo with git grep, in parallel,
+so .gitignore'd and binary files are skipped. Matches are printed as
+source/org/repo:path:line:text, in repo order. Pathspecs after -- limit the
+files searched (e.g. '*.go').
+
+Repos are selected as for dev exec. With --interactive, the matches are opened
+in a fuzzy finder and dev grep cd's into the repo of the chosen one. Repos that
+cannot be searched are listed, but the matches of the others are still shown.`,
+	Example: `  dev grep 'legacyClient\.Do' --filter github.com/mycompany
+  dev grep -iw todo -- '*.go'
+  dev grep --interactive NewLegacyClient`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		_, _, err := parseGrepArgs(args, cmd.ArgsLenAtDash())
+		return err
+	},
+	RunE: runGrep,
+}
+
+func init() {
+	addSelectionFlags(grepCmd)
+	grepCmd.Flags().BoolP("ignore-case", "i", false, "ignore case differences")
+	grepCmd.Flags().BoolP("word-regexp", "w", false, "match the pattern only at word boundaries")
+	grepCmd.Flags().BoolP("fixed-strings", "F", false, "treat the pattern as a literal string, not a regex")
+	grepCmd.Flags().Bool("interactive", false, "pick a match in a fuzzy finder and cd into its repo")
+	grepCmd.Flags().Int("parallel", pool.DefaultWorkers(), "number of repos searched concurrently")
+	summarizesFailures(grepCmd)
+	rootCmd.AddCommand(grepCmd)
+}
+
+// grepQuery is what dev grep searches for.
+type grepQuery struct {
+	pattern    string
+	pathspecs  []string
+	ignoreCase bool
+	word       bool
+	fixed      bool
+}
+
+// gitArgs returns the git grep invocation for q.
+func (q grepQuery) gitArgs() []string {
+	args := []string{"-c", "core.quotePath=false", "grep", "-n", "-I", "--no-color"}
+	if q.ignoreCase {
+		args = append(args, "-i")
+	}
+	if q.word {
+		args = append(args, "-w")
+	}
+	if q.fixed {
+		args = append(args, "-F")
+	}
+	args = append(args, "-e", q.pattern)
+	if len(q.pathspecs) > 0 {
+		args = append(args, "--")
+		args = append(args, q.pathspecs...)
+	}
+	return args
+}
+
+// grepResult holds the matches found in one repo, each formatted as
+// source/org/repo:path:line:text.
+type grepResult struct {
+	repo    string
+	matches []string
+	err     error
+}
+
+// parseGrepArgs splits args into the pattern and the pathspecs following
+// "--", whose position is dash (-1 without one).
+func parseGrepArgs(args []string, dash int) (string, []string, error) {
+	switch {
+	case len(args) == 0 || dash == 0:
+		return "", nil, fmt.Errorf("requires a pattern")
+	case dash == -1 && len(args) > 1, dash > 1:
+		return "", nil, fmt.Errorf("unexpected argument %q; put pathspecs after --", args[1])
+	}
+	return args[0], args[1:], nil
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	var q grepQuery
+	var err error
+	q.pattern, q.pathspecs, err = parseGrepArgs(args, cmd.ArgsLenAtDash())
+	if err != nil {
+		return err
+	}
+	q.ignoreCase, _ = cmd.Flags().GetBool("ignore-case")
+	q.word, _ = cmd.Flags().GetBool("word-regexp")
+	q.fixed, _ = cmd.Flags().GetBool("fixed-strings")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+	workers, _ := cmd.Flags().GetInt("parallel")
+
+	srcRoot, repoPaths, err := selectedRepos(cmd)
+	if err != nil {
+		return err
+	}
+	if len(repoPaths) == 0 {
+		return fmt.Errorf("no repos match the selection")
+	}
+
+	// In interactive mode stdout is reserved for the cd directive
+	var out io.Writer = os.Stdout
+	if interactive {
+		out = nil
+	}
+	results := grepRepos(srcRoot, repoPaths, q, workers, out)
+
+	var failures, items []string
+	repoOf := make(map[string]string)
+	for _, r := range results {
+		if r.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", r.repo, r.err))
+			continue
+		}
+		for _, m := range r.matches {
+			repoOf[m] = r.repo
+			items = append(items, m)
+		}
+	}
+	// A broken repo shouldn't hide the matches found in the others
+	var searchErr error
+	if len(failures) > 0 {
+		printFailures(os.Stderr, failures)
+		searchErr = fmt.Errorf("could not search %d of %d repos", len(failures), len(results))
+	}
+	if len(items) == 0 {
+		if searchErr != nil {
+			return searchErr
+		}
+		return fmt.Errorf("no matches for %q", q.pattern)
+	}
+	if !interactive {
+		return searchErr
+	}
+	// From here failures stay warnings, as an error would keep the shell wrapper from cd'ing
+
+	selected, err := fuzzy.Run(items)
+	if err != nil {
+		return err
+	}
+	if selected == "" {
+		return nil // User cancelled
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", selected)
+	fullPath := filepath.Join(srcRoot, repoOf[selected])
+	_ = history.Visit(fullPath)
+	return emitCD(os.Stdout, fullPath)
+}
+
+// grepRepos runs q in each of repoPaths (relative to srcRoot), at most
+// workers at a time. Unless out is nil, the matches are written to it as
+// they come in, keeping repo order. Results are in the order of repoPaths.
+func grepRepos(srcRoot string, repoPaths []string, q grepQuery, workers int, out io.Writer) []grepResult {
+	results := make([]grepResult, len(repoPaths))
+	done := make([]chan struct{}, len(repoPaths))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	go pool.Each(repoPaths, workers, func(i int, rel string) {
+		rel = filepath.ToSlash(rel)
+		matches, err := grepRepo(filepath.Join(srcRoot, rel), q)
+		for j, m := range matches {
+			matches[j] = rel + ":" + m
+		}
+		results[i] = grepResult{repo: rel, matches: matches, err: err}
+		close(done[i])
+	})
+
+	for i := range repoPaths {
+		<-done[i]
+		if out == nil {
+			continue
+		}
+		for _, m := range results[i].matches {
+			fmt.Fprintln(out, m)
+		}
+	}
+	return results
+}
+
+// grepRepo runs q with git grep in the repo at dir and returns its
+// path:line:text matches. Finding nothing is not an error.
+func grepRepo(dir string, q grepQuery) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("git", append([]string{"-C", dir}, q.gitArgs()...)...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && stderr.Len() == 0 {
+			return nil, nil
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git grep failed: %s", msg)
+		}
+		return nil, fmt.Errorf("git grep failed: %w", err)
+	}
+	return strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n"), nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// grepFixture creates a git repo at srcRoot/rel with files (path → content),
+// adding all but the ignored ones to the index.
+func grepFixture(t *testing.T, srcRoot, rel string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(srcRoot, rel)
+	initUpstream(t, dir)
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitOutput(t, dir, "add", ".")
+}
+
+func TestGrepRepos(t *testing.T) {
+	setupRemotes(t)
+	srcRoot := t.TempDir()
+	grepFixture(t, srcRoot, "github.com/me/web", map[string]string{
+		"README.md": "nothing to see\n",
+	})
+	grepFixture(t, srcRoot, "github.com/me/api", map[string]string{
+		".gitignore":     "vendor/\n",
+		"main.go":        "package main\n\nfunc main() { legacy.Do() }\n",
+		"docs/üse.md":    "Call Legacy.Do to start\n",
+		"vendor/lib.go":  "legacy.Do()\n",
+		"testdata/blob":  "legacy.Do\x00\x01",
+		"internal/x.txt": "not legacy\n",
+	})
+	if err := os.MkdirAll(filepath.Join(srcRoot, "github.com/me/notgit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	repoPaths := []string{"github.com/me/api", "github.com/me/web"}
+	var stdout bytes.Buffer
+	results := grepRepos(srcRoot, repoPaths, grepQuery{pattern: `legacy\.Do`, ignoreCase: true}, 2, &stdout)
+
+	want := []string{
+		"github.com/me/api:docs/üse.md:1:Call Legacy.Do to start",
+		"github.com/me/api:main.go:3:func main() { legacy.Do() }",
+	}
+	if len(results) != 2 || !reflect.DeepEqual(results[0].matches, want) || results[1].matches != nil {
+		t.Errorf("results = %+v, want %q in api only", results, want)
+	}
+	if got := stdout.String(); got != strings.Join(want, "\n")+"\n" {
+		t.Errorf("stdout = %q", got)
+	}
+
+	results = grepRepos(srcRoot, repoPaths, grepQuery{pattern: "legacy.Do", fixed: true, pathspecs: []string{"*.go"}}, 1, nil)
+	if want := []string{"github.com/me/api:main.go:3:func main() { legacy.Do() }"}; !reflect.DeepEqual(results[0].matches, want) {
+		t.Errorf("with pathspec, matches = %q, want %q", results[0].matches, want)
+	}
+
+	results = grepRepos(srcRoot, []string{"github.com/me/notgit"}, grepQuery{pattern: "x"}, 1, nil)
+	if results[0].err == nil {
+		t.Error("grep outside a git repo should fail")
+	}
+}
+
+func TestParseGrepArgs(t *testing.T) {
+	tests := []struct {
+		args      []string
+		dash      int
+		pattern   string
+		pathspecs []string
+		wantErr   bool
+	}{
+		{[]string{"foo"}, -1, "foo", []string{}, false},
+		{[]string{"foo", "*.go", "docs"}, 1, "foo", []string{"*.go", "docs"}, false},
+		{[]string{"foo", "bar"}, -1, "", nil, true}, // a pathspec without --
+		{[]string{"foo", "bar", "*.go"}, 2, "", nil, true},
+		{[]string{"*.go"}, 0, "", nil, true}, // no pattern before --
+		{nil, -1, "", nil, true},
+	}
+	for _, tt := range tests {
+		pattern, pathspecs, err := parseGrepArgs(tt.args, tt.dash)
+		if (err != nil) != tt.wantErr || pattern != tt.pattern || !reflect.DeepEqual(pathspecs, tt.pathspecs) {
+			t.Errorf("parseGrepArgs(%q, %d) = %q, %q, %v", tt.args, tt.dash, pattern, pathspecs, err)
+		}
+	}
+}
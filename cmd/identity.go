@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/identity"
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/spf13/cobra"
+)
+
+var identityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Manage per-org git identities",
+	Long:  `Check and apply the git identity profiles (name, email, signing key) configured for source/org patterns.`,
+}
+
+func init() {
+	rootCmd.AddCommand(identityCmd)
+}
+
+// applyIdentity writes the identity profile matching rp, if any, to the local
+// git config of its checkout in dir. Failures are reported as warnings.
+func applyIdentity(rp repourl.RepoPath, dir string, stderr io.Writer) {
+	cfg, err := config.Load()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(stderr, "warning: could not load config: %v\n", err)
+		}
+		return
+	}
+
+	id := cfg.IdentityFor(rp.FullPath())
+	if id == nil {
+		return
+	}
+	if err := identity.Apply(dir, *id); err != nil {
+		fmt.Fprintf(stderr, "warning: could not apply identity %q: %v\n", id.Profile, err)
+		return
+	}
+	fmt.Fprintf(stderr, "applied identity %q (%s)\n", id.Profile, id.Email)
+}
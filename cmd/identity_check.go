@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/identity"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/spf13/cobra"
+)
+
+var identityCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report repos whose git identity doesn't match their profile",
+	Long: `Scans every repo under the source root and compares its effective git
+user.name, user.email and user.signingkey with the identity profile matching
+its source/org/repo path. Repos without a matching profile are skipped.
+
+With --fix, writes the profile to the local git config of mismatched repos.
+Exits non-zero if mismatches remain.`,
+	Args: cobra.NoArgs,
+	RunE: runIdentityCheck,
+}
+
+func init() {
+	identityCheckCmd.Flags().Bool("fix", false, "apply the matching profile to mismatched repos")
+	identityCmd.AddCommand(identityCheckCmd)
+}
+
+func runIdentityCheck(cmd *cobra.Command, args []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+	if len(cfg.Identities) == 0 {
+		return fmt.Errorf("no identities configured in config file")
+	}
+
+	srcRoot := cfg.GetSrcRoot()
+	allRepos := repos.DiscoverCached(srcRoot)
+
+	remaining := checkIdentities(cfg, srcRoot, allRepos, fix, os.Stdout, os.Stderr)
+	if remaining > 0 {
+		return fmt.Errorf("%d repo(s) with a mismatched identity", remaining)
+	}
+	return nil
+}
+
+// checkIdentities prints one line per mismatched key to stdout and, with fix,
+// applies the matching profile. It returns the number of repos still mismatched.
+func checkIdentities(cfg *config.Config, srcRoot string, repoPaths []string, fix bool, stdout, stderr io.Writer) int {
+	var checked, mismatched, fixed int
+	for _, repo := range repoPaths {
+		id := cfg.IdentityFor(repo)
+		if id == nil {
+			continue
+		}
+		checked++
+
+		dir := filepath.Join(srcRoot, repo)
+		mismatches, err := identity.Check(dir, *id)
+		if err != nil {
+			fmt.Fprintf(stderr, "warning: %s: %v\n", repo, err)
+			continue
+		}
+		if len(mismatches) == 0 {
+			continue
+		}
+		mismatched++
+
+		for _, m := range mismatches {
+			got := m.Got
+			if got == "" {
+				got = "(unset)"
+			}
+			fmt.Fprintf(stdout, "%s: %s is %s, want %s (profile %s)\n", repo, m.Key, got, m.Want, id.Profile)
+		}
+		if fix {
+			if err := identity.Apply(dir, *id); err != nil {
+				fmt.Fprintf(stderr, "warning: %s: %v\n", repo, err)
+				continue
+			}
+			fixed++
+		}
+	}
+
+	if fix {
+		fmt.Fprintf(stderr, "checked %d repos, fixed %d of %d mismatched\n", checked, fixed, mismatched)
+	} else {
+		fmt.Fprintf(stderr, "checked %d repos, %d mismatched\n", checked, mismatched)
+	}
+	return mismatched - fixed
+}
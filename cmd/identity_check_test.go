@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+func TestCheckIdentities(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	root := t.TempDir()
+	repoPaths := []string{"github.com/mycompany/api", "github.com/mycompany/web", "github.com/me/dotfiles"}
+	for _, r := range repoPaths {
+		dir := filepath.Join(root, r)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := exec.Command("git", "-C", filepath.Join(root, "github.com/mycompany/web"), "config", "user.email", "me@mycompany.com").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Identities: []config.Identity{
+		{Profile: "work", Email: "me@mycompany.com", Match: []string{"github.com/mycompany/*"}},
+	}}
+
+	var stdout, stderr bytes.Buffer
+	if n := checkIdentities(cfg, root, repoPaths, false, &stdout, &stderr); n != 1 {
+		t.Errorf("checkIdentities() = %d, want 1 mismatched repo", n)
+	}
+	if want := "github.com/mycompany/api: user.email is (unset), want me@mycompany.com (profile work)\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if !strings.Contains(stderr.String(), "checked 2 repos, 1 mismatched") {
+		t.Errorf("stderr = %q, want summary", stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if n := checkIdentities(cfg, root, repoPaths, true, &stdout, &stderr); n != 0 {
+		t.Errorf("checkIdentities(fix) = %d, want 0 remaining", n)
+	}
+	stdout.Reset()
+	if n := checkIdentities(cfg, root, repoPaths, false, &stdout, &stderr); n != 0 || stdout.Len() != 0 {
+		t.Errorf("after fix: %d mismatched, stdout %q; want none", n, stdout.String())
+	}
+}
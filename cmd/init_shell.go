@@ -2,17 +2,34 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/dsaiztc/dev/internal/shell"
 	"github.com/spf13/cobra"
 )
 
 var initCmd = &cobra.Command{
-	Use:   "init",
+	Use:   "init [shell]",
 	Short: "Print shell wrapper function",
-	Long:  `Prints a shell function to stdout. Add eval "$(dev init)" to your ~/.zshrc or ~/.bashrc.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(shell.WrapperFunc())
+	Long: `Prints a shell function to stdout. Without an argument, the shell is detected from $SHELL.
+
+  bash/zsh: add eval "$(dev init)" to your ~/.zshrc or ~/.bashrc
+  fish:     add dev init fish | source to your ~/.config/fish/config.fish
+  pwsh:     add dev init pwsh | Out-String | Invoke-Expression to your $PROFILE
+  nu:       run dev init nu | save -f ~/.config/nushell/dev.nu and add source ~/.config/nushell/dev.nu to config.nu`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: shell.Supported(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := shell.Detect(os.Getenv("SHELL"))
+		if len(args) == 1 {
+			name = args[0]
+		}
+		wrapper, err := shell.Wrapper(name)
+		if err != nil {
+			return err
+		}
+		fmt.Println(wrapper)
+		return nil
 	},
 }
 
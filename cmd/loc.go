@@ -2,20 +2,22 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/dsaiztc/dev/internal/config"
 	"github.com/dsaiztc/dev/internal/fuzzy"
+	"github.com/dsaiztc/dev/internal/history"
 	"github.com/dsaiztc/dev/internal/repos"
 	"github.com/spf13/cobra"
 )
 
 var locCmd = &cobra.Command{
-	Use:   "loc [query]",
-	Short: "Locate and print the full path to a repository",
-	Long:  `Without arguments, opens an interactive fuzzy finder. With a query, prints the path to the best matching repo.`,
-	RunE:  runLoc,
+	Use:               "loc [query]",
+	Short:             "Locate and print the full path to a repository",
+	Long:              `Without arguments, opens an interactive fuzzy finder. With a query, prints the path to the best matching repo.`,
+	ValidArgsFunction: completeRepos,
+	RunE:              runLoc,
 }
 
 func init() {
@@ -23,27 +25,23 @@ func init() {
 }
 
 func runLoc(cmd *cobra.Command, args []string) error {
-	homeDir, err := os.UserHomeDir()
+	baseDir, err := config.SrcRoot()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
-	}
-
-	baseDir := filepath.Join(homeDir, "src")
-
-	allRepos, err := repos.Discover(baseDir)
-	if err != nil {
-		return fmt.Errorf("could not discover repos: %w", err)
+		return err
 	}
 
+	allRepos := repos.DiscoverCached(baseDir)
 	if len(allRepos) == 0 {
 		return fmt.Errorf("no repos found under %s", baseDir)
 	}
 
+	scores := history.ScoresUnderRoot(baseDir)
+
 	var selected string
 
 	if len(args) == 0 {
-		// Interactive fuzzy finder
-		selected, err = fuzzy.Run(allRepos)
+		// Interactive fuzzy finder, most frecent repos first
+		selected, err = fuzzy.Run(repos.SortByFrecency(allRepos, scores))
 		if err != nil {
 			return err
 		}
@@ -53,7 +51,7 @@ func runLoc(cmd *cobra.Command, args []string) error {
 	} else {
 		// Fuzzy match with query
 		query := strings.Join(args, " ")
-		matches := repos.FuzzyMatch(allRepos, query)
+		matches := repos.FuzzyMatch(allRepos, query, scores)
 		if len(matches) == 0 {
 			return fmt.Errorf("no repos matching %q", query)
 		}
@@ -61,6 +59,7 @@ func runLoc(cmd *cobra.Command, args []string) error {
 	}
 
 	fullPath := filepath.Join(baseDir, selected)
+	_ = history.Visit(fullPath)
 	fmt.Println(fullPath)
 	return nil
 }
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/shell"
+)
+
+// runAsDevEnv makes the test binary run the dev CLI instead of the tests.
+const runAsDevEnv = "DEV_TEST_RUN_AS_DEV"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runAsDevEnv) == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// devOnPath puts a dev executable that runs this test binary as the dev CLI
+// in a new directory, and prepends that directory to PATH.
+func devOnPath(t *testing.T) {
+	t.Helper()
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" + runAsDevEnv + "=1 exec " + shell.Quote(self) + " \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "dev"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
@@ -11,6 +11,11 @@ import (
 	"strings"
 
 	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/forge"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/dsaiztc/dev/internal/scaffold"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +29,10 @@ var newCmd = &cobra.Command{
 func init() {
 	newCmd.Flags().String("source", "", "override default source (e.g. github.com)")
 	newCmd.Flags().String("org", "", "override default org (e.g. dsaiztc)")
+	newCmd.Flags().Bool("no-hooks", false, "skip the post-new repo hooks")
+	newCmd.Flags().String("template", "", "scaffold the project from a template (name, path, or git URL)")
+	newCmd.Flags().Bool("remote", false, "also create the repo on its forge (GitHub, GitLab or Gitea) and push to it")
+	newCmd.Flags().Bool("public", false, "make the --remote repo public instead of private")
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -61,19 +70,89 @@ func runNew(cmd *cobra.Command, args []string) error {
 		org = cfg.DefaultOrg
 	}
 
-	return createProject(homeDir, source, org, name, os.Stdout, os.Stderr)
+	srcRoot := cfg.GetSrcRoot()
+	rp := repourl.RepoPath{Source: source, Org: org, Project: name}
+	dir := filepath.Join(srcRoot, rp.FullPath())
+
+	// Resolve the template and ask for its variables before creating anything
+	templateRef, _ := cmd.Flags().GetString("template")
+	var templateDir string
+	var vars map[string]string
+	if templateRef != "" {
+		if _, err := os.Stat(dir); err == nil {
+			return fmt.Errorf("%s already exists; --template only applies to new projects", dir)
+		}
+		templateDir, err = scaffold.Resolve(templateRef, cfg)
+		if err != nil {
+			return err
+		}
+		manifest, err := scaffold.LoadManifest(templateDir)
+		if err != nil {
+			return err
+		}
+		vars, err = promptTemplateVars(manifest, rp)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Resolve the forge before creating anything so a missing token fails early
+	remote, _ := cmd.Flags().GetBool("remote")
+	var forgeSettings forge.Settings
+	if remote {
+		if _, err := os.Stat(dir); err == nil {
+			return fmt.Errorf("%s already exists; --remote only applies to new projects", dir)
+		}
+		forgeSettings, err = forge.Resolve(source, cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	created, err := createProject(srcRoot, source, org, name, os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	// A failed publish leaves a usable local project, so the remaining steps still run
+	var remoteErr error
+	if created {
+		applyIdentity(rp, dir, os.Stderr)
+		if templateDir != "" {
+			if err := applyTemplate(templateDir, dir, templateRef, vars, os.Stderr); err != nil {
+				return err
+			}
+		}
+		if remote {
+			public, _ := cmd.Flags().GetBool("public")
+			client := forge.New(forgeSettings)
+			if err := publishRemote(cmd.Context(), client, forgeSettings.Protocol, rp, dir, !public, os.Stderr); err != nil {
+				remoteErr = fmt.Errorf("project created locally at %s, but %w", dir, err)
+			}
+		}
+		if noHooks, _ := cmd.Flags().GetBool("no-hooks"); !noHooks {
+			runRepoHooks(hooks.PostNew, rp, dir, os.Stderr)
+		}
+	}
+
+	if err := repos.UpdateIndex(srcRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not update repo index: %v\n", err)
+	}
+	return remoteErr
 }
 
 // createProject creates the project directory, runs git init, and prints the
-// cd command to stdout. It is extracted from runNew for testability.
-func createProject(homeDir, source, org, name string, stdout, stderr io.Writer) error {
-	targetDir := filepath.Join(homeDir, "src", source, org, name)
+// cd directive to stdout. It reports whether the directory was newly created.
+// It is extracted from runNew for testability.
+func createProject(srcRoot, source, org, name string, stdout, stderr io.Writer) (bool, error) {
+	targetDir := filepath.Join(srcRoot, source, org, name)
 
+	created := false
 	if info, err := os.Stat(targetDir); err == nil && info.IsDir() {
 		fmt.Fprintf(stderr, "already exists: %s\n", targetDir)
 	} else {
 		if err := os.MkdirAll(targetDir, 0o755); err != nil {
-			return fmt.Errorf("could not create directory: %w", err)
+			return false, fmt.Errorf("could not create directory: %w", err)
 		}
 		fmt.Fprintf(stderr, "created %s\n", targetDir)
 
@@ -81,12 +160,12 @@ func createProject(homeDir, source, org, name string, stdout, stderr io.Writer)
 		gitCmd.Stdout = stderr
 		gitCmd.Stderr = stderr
 		if err := gitCmd.Run(); err != nil {
-			return fmt.Errorf("git init failed: %w", err)
+			return false, fmt.Errorf("git init failed: %w", err)
 		}
+		created = true
 	}
 
-	fmt.Fprintf(stdout, "cd %s\n", targetDir)
-	return nil
+	return created, emitCD(stdout, targetDir)
 }
 
 func promptForConfig(homeDir string) (*config.Config, error) {
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/forge"
+	"github.com/dsaiztc/dev/internal/repourl"
+)
+
+// publishRemote creates rp on its forge through client, adds it as origin of
+// the local repo at dir and pushes the current branch. A repo without commits
+// gets an empty initial commit first so there is something to push.
+func publishRemote(ctx context.Context, client forge.Client, protocol string, rp repourl.RepoPath, dir string, private bool, stderr io.Writer) error {
+	if err := exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		if err := gitRun(dir, "commit", "--quiet", "--allow-empty", "-m", "Initial commit"); err != nil {
+			return fmt.Errorf("could not make the initial commit: %w", err)
+		}
+	}
+
+	repo, err := client.CreateRepo(ctx, rp.Org, rp.Project, private)
+	if err != nil {
+		return err
+	}
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	fmt.Fprintf(stderr, "created %s repo %s\n", visibility, repo.WebURL)
+
+	remoteURL := repo.CloneURL(protocol)
+	if err := gitRun(dir, "remote", "add", "origin", remoteURL); err != nil {
+		return fmt.Errorf("could not add origin %s: %w", remoteURL, err)
+	}
+	push := exec.Command("git", "-C", dir, "push", "--quiet", "-u", "origin", "HEAD")
+	push.Stdout = stderr
+	push.Stderr = stderr
+	if err := push.Run(); err != nil {
+		return fmt.Errorf("could not push to %s: %w", remoteURL, err)
+	}
+	fmt.Fprintf(stderr, "pushed to %s\n", remoteURL)
+	return nil
+}
+
+// gitRun runs git with args in dir, returning its trimmed output as the error.
+func gitRun(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return nil
+}
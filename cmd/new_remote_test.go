@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/forge"
+	"github.com/dsaiztc/dev/internal/repourl"
+)
+
+// fakeForge creates repos as local bare repos and lists repos.
+type fakeForge struct {
+	dir     string
+	private bool
+	repos   []forge.Repo
+}
+
+func (f *fakeForge) ListRepos(context.Context, string) ([]forge.Repo, error) {
+	return f.repos, nil
+}
+
+func (f *fakeForge) CreateRepo(_ context.Context, org, name string, private bool) (*forge.Repo, error) {
+	f.private = private
+	bare := filepath.Join(f.dir, org, name+".git")
+	if out, err := exec.Command("git", "init", "-q", "--bare", bare).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("git init --bare: %v: %s", err, out)
+	}
+	return &forge.Repo{Path: org + "/" + name, SSHURL: bare, WebURL: "https://example.com/" + org + "/" + name}, nil
+}
+
+func TestPublishRemote(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", "-b", "main", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	client := &fakeForge{dir: t.TempDir()}
+	rp := repourl.RepoPath{Source: "github.com", Org: "mycompany", Project: "api"}
+	var stderr bytes.Buffer
+	if err := publishRemote(context.Background(), client, "ssh", rp, dir, true, &stderr); err != nil {
+		t.Fatalf("publishRemote: %v (stderr: %s)", err, stderr.String())
+	}
+	if !client.private {
+		t.Error("repo was not created private")
+	}
+	if !strings.Contains(stderr.String(), "created private repo https://example.com/mycompany/api") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	// The empty initial commit was pushed and is tracked
+	bare := filepath.Join(client.dir, "mycompany", "api.git")
+	out, err := exec.Command("git", "-C", bare, "log", "--format=%s", "main").Output()
+	if err != nil {
+		t.Fatalf("git log in remote: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "Initial commit" {
+		t.Errorf("remote log = %q, want %q", got, "Initial commit")
+	}
+	out, err = exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "main@{upstream}").Output()
+	if err != nil {
+		t.Fatalf("no upstream set: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "origin/main" {
+		t.Errorf("upstream = %q, want origin/main", got)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/dsaiztc/dev/internal/scaffold"
+)
+
+// templateVars returns the placeholder values for a new project: the
+// built-in name, org, source and module (source/org/name), followed by the
+// manifest's variables, which are prompted for on in with prompts written to
+// out. An empty answer takes the variable's default.
+func templateVars(m scaffold.Manifest, rp repourl.RepoPath, in io.Reader, out io.Writer) (map[string]string, error) {
+	vars := map[string]string{
+		"name":   rp.Project,
+		"org":    rp.Org,
+		"source": rp.Source,
+		"module": rp.FullPath(),
+	}
+
+	reader := bufio.NewReader(in)
+	for _, v := range m.Variables {
+		prompt := v.Prompt
+		if prompt == "" {
+			prompt = v.Name
+		}
+		def := scaffold.Substitute(v.Default, vars)
+		if def != "" {
+			fmt.Fprintf(out, "%s [%s]: ", prompt, def)
+		} else {
+			fmt.Fprintf(out, "%s: ", prompt)
+		}
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || answer == "") {
+			return nil, fmt.Errorf("could not read input: %w", err)
+		}
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			answer = def
+		}
+		vars[v.Name] = answer
+	}
+	return vars, nil
+}
+
+// promptTemplateVars is templateVars reading answers from /dev/tty, which is
+// only opened when the template declares variables.
+func promptTemplateVars(m scaffold.Manifest, rp repourl.RepoPath) (map[string]string, error) {
+	if len(m.Variables) == 0 {
+		return templateVars(m, rp, strings.NewReader(""), os.Stderr)
+	}
+	// Read from /dev/tty since stdin is captured by the $() subshell
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return nil, fmt.Errorf("could not open /dev/tty: %w", err)
+	}
+	defer tty.Close()
+	return templateVars(m, rp, tty, os.Stderr)
+}
+
+// applyTemplate renders the template into the freshly initialized project
+// at dir and makes the initial commit.
+func applyTemplate(templateDir, dir, ref string, vars map[string]string, stderr io.Writer) error {
+	if err := scaffold.Render(templateDir, dir, vars); err != nil {
+		return fmt.Errorf("could not render template %q: %w", ref, err)
+	}
+	fmt.Fprintf(stderr, "rendered template %q\n", ref)
+
+	if err := scaffold.Commit(dir, fmt.Sprintf("Initial commit from template %s", ref)); err != nil {
+		fmt.Fprintf(stderr, "warning: could not make the initial commit: %v\n", err)
+	}
+	return nil
+}
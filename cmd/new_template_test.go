@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/dsaiztc/dev/internal/scaffold"
+)
+
+func TestTemplateVars(t *testing.T) {
+	m := scaffold.Manifest{Variables: []scaffold.Variable{
+		{Name: "description", Prompt: "Description", Default: "The {{name}} service"},
+		{Name: "port"},
+	}}
+	rp := repourl.RepoPath{Source: "github.com", Org: "mycompany", Project: "api"}
+	var out bytes.Buffer
+
+	vars, err := templateVars(m, rp, strings.NewReader("\n8080\n"), &out)
+	if err != nil {
+		t.Fatalf("templateVars: %v", err)
+	}
+	want := map[string]string{
+		"name":        "api",
+		"org":         "mycompany",
+		"source":      "github.com",
+		"module":      "github.com/mycompany/api",
+		"description": "The api service",
+		"port":        "8080",
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("vars[%q] = %q, want %q", k, vars[k], v)
+		}
+	}
+	if got := out.String(); got != "Description [The api service]: port: " {
+		t.Errorf("prompts = %q", got)
+	}
+}
+
+func TestTemplateVars_NoInput(t *testing.T) {
+	m := scaffold.Manifest{Variables: []scaffold.Variable{{Name: "port"}}}
+	if _, err := templateVars(m, repourl.RepoPath{}, strings.NewReader(""), &bytes.Buffer{}); err == nil {
+		t.Error("expected error when input ends before all variables are answered")
+	}
+}
@@ -6,18 +6,23 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/dsaiztc/dev/internal/shell"
 )
 
 func TestCreateProject_NewDir(t *testing.T) {
-	home := t.TempDir()
+	root := t.TempDir()
 	var stdout, stderr bytes.Buffer
 
-	err := createProject(home, "github.com", "testuser", "my-project", &stdout, &stderr)
+	created, err := createProject(root, "github.com", "testuser", "my-project", &stdout, &stderr)
 	if err != nil {
 		t.Fatalf("createProject: %v", err)
 	}
+	if !created {
+		t.Error("expected createProject to report a new directory")
+	}
 
-	targetDir := filepath.Join(home, "src", "github.com", "testuser", "my-project")
+	targetDir := filepath.Join(root, "github.com", "testuser", "my-project")
 
 	// Directory should exist
 	info, err := os.Stat(targetDir)
@@ -34,8 +39,8 @@ func TestCreateProject_NewDir(t *testing.T) {
 		t.Errorf("expected .git directory: %v", err)
 	}
 
-	// stdout should contain the cd command
-	wantCD := "cd " + targetDir + "\n"
+	// stdout should contain the cd directive
+	wantCD := shell.DirectiveCD + " " + shell.Quote(targetDir) + "\n"
 	if stdout.String() != wantCD {
 		t.Errorf("stdout = %q, want %q", stdout.String(), wantCD)
 	}
@@ -47,20 +52,23 @@ func TestCreateProject_NewDir(t *testing.T) {
 }
 
 func TestCreateProject_ExistingDir(t *testing.T) {
-	home := t.TempDir()
-	targetDir := filepath.Join(home, "src", "github.com", "testuser", "existing")
+	root := t.TempDir()
+	targetDir := filepath.Join(root, "github.com", "testuser", "existing")
 	if err := os.MkdirAll(targetDir, 0o755); err != nil {
 		t.Fatalf("setup: %v", err)
 	}
 
 	var stdout, stderr bytes.Buffer
-	err := createProject(home, "github.com", "testuser", "existing", &stdout, &stderr)
+	created, err := createProject(root, "github.com", "testuser", "existing", &stdout, &stderr)
 	if err != nil {
 		t.Fatalf("createProject: %v", err)
 	}
+	if created {
+		t.Error("expected createProject to report an existing directory")
+	}
 
-	// stdout should still have the cd command
-	wantCD := "cd " + targetDir + "\n"
+	// stdout should still have the cd directive
+	wantCD := shell.DirectiveCD + " " + shell.Quote(targetDir) + "\n"
 	if stdout.String() != wantCD {
 		t.Errorf("stdout = %q, want %q", stdout.String(), wantCD)
 	}
@@ -78,15 +86,15 @@ func TestCreateProject_ExistingDir(t *testing.T) {
 }
 
 func TestCreateProject_NestedPath(t *testing.T) {
-	home := t.TempDir()
+	root := t.TempDir()
 	var stdout, stderr bytes.Buffer
 
-	err := createProject(home, "gitlab.com", "myteam", "deep-project", &stdout, &stderr)
+	_, err := createProject(root, "gitlab.com", "myteam", "deep-project", &stdout, &stderr)
 	if err != nil {
 		t.Fatalf("createProject: %v", err)
 	}
 
-	targetDir := filepath.Join(home, "src", "gitlab.com", "myteam", "deep-project")
+	targetDir := filepath.Join(root, "gitlab.com", "myteam", "deep-project")
 	if _, err := os.Stat(targetDir); err != nil {
 		t.Fatalf("expected directory to exist: %v", err)
 	}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/history"
+	"github.com/spf13/cobra"
+)
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "Show the most frequently and recently visited directories",
+	Long:  `Lists the directories selected through cd, loc and wkt cd, ranked by frecency (visit count decayed by time since the last visit).`,
+	Args:  cobra.NoArgs,
+	RunE:  runRecent,
+}
+
+func init() {
+	recentCmd.Flags().IntP("limit", "n", 10, "number of entries to show (0 for all)")
+	rootCmd.AddCommand(recentCmd)
+}
+
+func runRecent(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	h, err := history.Load()
+	if err != nil {
+		return fmt.Errorf("could not load history: %w", err)
+	}
+
+	now := time.Now()
+	var shown int
+	for _, e := range h.Top(0, now) {
+		if limit > 0 && shown == limit {
+			break
+		}
+		// Skip directories that were removed since they were visited
+		if _, err := os.Stat(e.Path); err != nil {
+			continue
+		}
+		fmt.Printf("%8.2f  %s\n", e.Score(now), e.Path)
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Fprintln(os.Stderr, "no history yet")
+	}
+	return nil
+}
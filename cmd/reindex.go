@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the repository index from scratch",
+	Long:  `Walks the whole source root and rewrites the repository index (~/.cache/dev/repos.json) used by cd, loc and tree.`,
+	Args:  cobra.NoArgs,
+	RunE:  runReindex,
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	baseDir, err := config.SrcRoot()
+	if err != nil {
+		return err
+	}
+
+	allRepos, err := repos.Reindex(baseDir)
+	if err != nil {
+		return fmt.Errorf("could not rebuild repo index: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "indexed %d repos under %s\n", len(allRepos), baseDir)
+	return nil
+}
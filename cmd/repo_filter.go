@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/repourl"
+	"github.com/spf13/cobra"
+)
+
+// matchRepoGlob reports whether the repo at rel (source/org/repo) matches
+// pattern, a repourl.Match pattern. A pattern also matches everything below
+// it, so "github.com/mycompany" selects all of the org's repos.
+func matchRepoGlob(pattern, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	return repourl.Match(pattern, rel) || repourl.Match(pattern+"/**", rel)
+}
+
+// filterRepos returns the repos matching any of patterns, or all of them if
+// there are no patterns.
+func filterRepos(repoPaths, patterns []string) []string {
+	if len(patterns) == 0 {
+		return repoPaths
+	}
+	var matched []string
+	for _, rel := range repoPaths {
+		for _, p := range patterns {
+			if matchRepoGlob(p, rel) {
+				matched = append(matched, rel)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// repoSelection narrows down the repos a multi-repo command runs over. Each
+// criterion that is set must match; with none set, every repo is selected.
+type repoSelection struct {
+	patterns []string // source/org/repo globs, any of which may match
+	query    string   // fuzzy query over the repo path
+	group    string   // name of a group of globs in the config
+}
+
+// addSelectionFlags registers the --filter, --query and --group flags.
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("filter", nil, "only repos matching these source/org/repo globs")
+	cmd.Flags().StringP("query", "q", "", "only repos fuzzy-matching this query")
+	cmd.Flags().StringP("group", "g", "", "only repos in this group from the config")
+}
+
+// selectionFromFlags reads the flags registered by addSelectionFlags.
+func selectionFromFlags(cmd *cobra.Command) repoSelection {
+	var sel repoSelection
+	sel.patterns, _ = cmd.Flags().GetStringSlice("filter")
+	sel.query, _ = cmd.Flags().GetString("query")
+	sel.group, _ = cmd.Flags().GetString("group")
+	return sel
+}
+
+// selectRepos returns the repos of repoPaths picked by sel, in path order.
+// Groups are looked up in cfg.
+func selectRepos(repoPaths []string, sel repoSelection, cfg *config.Config) ([]string, error) {
+	selected := filterRepos(repoPaths, sel.patterns)
+
+	if sel.group != "" {
+		globs, ok := cfg.Groups[sel.group]
+		if !ok {
+			names := make([]string, 0, len(cfg.Groups))
+			for name := range cfg.Groups {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if len(names) == 0 {
+				return nil, fmt.Errorf("unknown group %q; define groups under \"groups\" in the config file", sel.group)
+			}
+			return nil, fmt.Errorf("unknown group %q (known: %s)", sel.group, strings.Join(names, ", "))
+		}
+		if len(globs) == 0 {
+			return nil, nil
+		}
+		selected = filterRepos(selected, globs)
+	}
+
+	if sel.query != "" {
+		selected = repos.FuzzyMatch(selected, sel.query, nil)
+		slices.Sort(selected)
+	}
+	return selected, nil
+}
+
+// selectedRepos discovers the repos under the source root and narrows them
+// down with the flags registered by addSelectionFlags. Repo paths are
+// relative to the returned source root.
+func selectedRepos(cmd *cobra.Command) (string, []string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", nil, fmt.Errorf("could not load config: %w", err)
+		}
+		cfg = &config.Config{}
+	}
+	srcRoot, err := config.SrcRoot()
+	if err != nil {
+		return "", nil, err
+	}
+	allRepos := repos.DiscoverCached(srcRoot)
+	repoPaths, err := selectRepos(allRepos, selectionFromFlags(cmd), cfg)
+	return srcRoot, repoPaths, err
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+func TestFilterRepos(t *testing.T) {
+	all := []string{"github.com/mycompany/api", "github.com/mycompany/web", "github.com/me/dotfiles", "gitlab.com/group/sub/tool"}
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{nil, all},
+		{[]string{"github.com/mycompany"}, []string{"github.com/mycompany/api", "github.com/mycompany/web"}},
+		{[]string{"*/*/api", "gitlab.com"}, []string{"github.com/mycompany/api", "gitlab.com/group/sub/tool"}},
+		{[]string{"github.com/*/d*"}, []string{"github.com/me/dotfiles"}},
+		{[]string{"bitbucket.org"}, nil},
+	}
+	for _, tt := range tests {
+		got := filterRepos(all, tt.patterns)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("filterRepos(%v) = %v, want %v", tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestSelectRepos(t *testing.T) {
+	all := []string{"github.com/mycompany/api", "github.com/mycompany/web", "github.com/me/api-client", "gitlab.com/group/tool"}
+	cfg := &config.Config{Groups: map[string][]string{
+		"backend": {"github.com/mycompany/api", "gitlab.com"},
+		"empty":   {},
+	}}
+	tests := []struct {
+		name    string
+		sel     repoSelection
+		want    []string
+		wantErr string
+	}{
+		{name: "everything", sel: repoSelection{}, want: all},
+		{name: "group", sel: repoSelection{group: "backend"}, want: []string{"github.com/mycompany/api", "gitlab.com/group/tool"}},
+		{name: "group and filter", sel: repoSelection{group: "backend", patterns: []string{"github.com"}}, want: []string{"github.com/mycompany/api"}},
+		{name: "query", sel: repoSelection{query: "api"}, want: []string{"github.com/me/api-client", "github.com/mycompany/api"}},
+		{name: "query and filter", sel: repoSelection{query: "api", patterns: []string{"github.com/mycompany"}}, want: []string{"github.com/mycompany/api"}},
+		{name: "empty group", sel: repoSelection{group: "empty"}, want: nil},
+		{name: "unknown group", sel: repoSelection{group: "frontend"}, wantErr: `unknown group "frontend" (known: backend, empty)`},
+	}
+	for _, tt := range tests {
+		got, err := selectRepos(all, tt.sel, cfg)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: selected %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
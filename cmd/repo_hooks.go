@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/repourl"
+)
+
+// runRepoHooks runs the configured repo_hooks commands for the named hook in
+// dir, the checkout of rp. Committed .dev/hooks scripts are not run, since the
+// repo may have just been cloned from anywhere. The repo already exists when
+// this runs, so failures are reported as warnings rather than errors.
+func runRepoHooks(name string, rp repourl.RepoPath, dir string, stderr io.Writer) {
+	cfg, err := config.Load()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(stderr, "warning: could not load config: %v\n", err)
+		}
+		return
+	}
+
+	env := map[string]string{
+		"DEV_HOOK":      name,
+		"DEV_REPO":      rp.FullPath(),
+		"DEV_REPO_PATH": dir,
+		"DEV_SOURCE":    rp.Source,
+		"DEV_ORG":       rp.Org,
+		"DEV_PROJECT":   rp.Project,
+	}
+	if err := hooks.RunCommands(name, dir, cfg.RepoHookCommands(rp.FullPath(), name), env, stderr); err != nil {
+		fmt.Fprintf(stderr, "warning: %v\n", err)
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/repourl"
+)
+
+func TestRunRepoHooks(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := &config.Config{
+		RepoHooks: []config.RepoHook{
+			{Match: "github.com/mycompany/*", Hooks: config.Hooks{
+				hooks.PostClone: {`echo "$DEV_REPO $DEV_SOURCE $DEV_ORG $DEV_PROJECT $DEV_REPO_PATH" > hook.out`},
+			}},
+			{Match: "github.com/other/*", Hooks: config.Hooks{hooks.PostClone: {"touch other.out"}}},
+		},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+
+	rp := repourl.RepoPath{Source: "github.com", Org: "mycompany", Project: "api"}
+	var stderr bytes.Buffer
+	runRepoHooks(hooks.PostClone, rp, dir, &stderr)
+
+	data, err := os.ReadFile(filepath.Join(dir, "hook.out"))
+	if err != nil {
+		t.Fatalf("hook did not run: %v (stderr: %s)", err, stderr.String())
+	}
+	if want := "github.com/mycompany/api github.com mycompany api " + dir + "\n"; string(data) != want {
+		t.Errorf("hook env = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.out")); !os.IsNotExist(err) {
+		t.Error("hook for a non-matching pattern ran")
+	}
+}
+
+func TestRunRepoHooks_FailureIsWarning(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{
+		RepoHooks: []config.RepoHook{{Match: "**", Hooks: config.Hooks{hooks.PostNew: {"exit 1"}}}},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+
+	var stderr bytes.Buffer
+	runRepoHooks(hooks.PostNew, repourl.RepoPath{Source: "github.com", Org: "o", Project: "p"}, t.TempDir(), &stderr)
+	if !strings.Contains(stderr.String(), "warning: post-new hook failed") {
+		t.Errorf("stderr = %q, want a warning", stderr.String())
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/manifest"
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/dsaiztc/dev/internal/repos"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <manifest>",
+	Short: "Clone the repos of a manifest that are missing",
+	Long: `Clones every repo listed in a manifest (see dev export) that is not under
+the source root yet, checking out its branch if one is given, and
+creates the manifest's worktrees that do not exist yet. Repos under the source
+root that the manifest does not list are reported but left alone.
+
+The manifest is read as YAML if its name ends in .yaml or .yml, and as JSON
+otherwise.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().Int("parallel", pool.DefaultWorkers(), "number of concurrent clones")
+	restoreCmd.Flags().Bool("no-hooks", false, "skip the post-clone repo hooks and post-wkt-new hooks")
+	restoreCmd.Flags().Bool("dry-run", false, "list the repos that would be cloned")
+	summarizesFailures(restoreCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	m, err := manifest.Load(args[0])
+	if err != nil {
+		return err
+	}
+	srcRoot, err := config.SrcRoot()
+	if err != nil {
+		return err
+	}
+
+	workers, _ := cmd.Flags().GetInt("parallel")
+	noHooks, _ := cmd.Flags().GetBool("no-hooks")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return restoreManifest(m, srcRoot, workers, noHooks, dryRun, os.Stdout, os.Stderr)
+}
+
+// restoreManifest clones the repos of m missing under srcRoot and creates
+// their missing worktrees, then reports the repos m does not list. With
+// dryRun the repos that would be cloned are listed on stdout instead. It
+// fails if any clone or worktree could not be created.
+func restoreManifest(m *manifest.Manifest, srcRoot string, workers int, noHooks, dryRun bool, stdout, stderr io.Writer) error {
+	listed := make(map[string]bool)
+	var todo []pendingClone
+	var failures []string
+	for _, r := range m.Repos {
+		rp, err := r.Path()
+		if err != nil {
+			return err
+		}
+		listed[rp.FullPath()] = true
+		dir := filepath.Join(srcRoot, rp.FullPath())
+		if exists, err := checkCloneTarget(dir); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", rp.FullPath(), err))
+		} else if !exists {
+			todo = append(todo, pendingClone{rp: rp, url: r.URL, branch: r.Branch, dir: dir})
+		}
+	}
+	fmt.Fprintf(stderr, "%d repos in manifest, %d to clone\n", len(m.Repos), len(todo))
+
+	if dryRun {
+		for _, c := range todo {
+			fmt.Fprintln(stdout, c.rp.FullPath())
+		}
+	} else {
+		cloned, cloneFailures := cloneAll(todo, workers, noHooks, stderr)
+		failures = append(failures, cloneFailures...)
+		fmt.Fprintf(stderr, "cloned %d repos\n", len(cloned))
+		failures = append(failures, restoreWorktrees(m, srcRoot, noHooks, stderr)...)
+
+		if err := repos.UpdateIndex(srcRoot); err != nil {
+			fmt.Fprintf(stderr, "warning: could not update repo index: %v\n", err)
+		}
+	}
+
+	discovered, err := repos.Discover(srcRoot)
+	if err != nil {
+		return fmt.Errorf("could not discover repos: %w", err)
+	}
+	var extras []string
+	for _, rel := range discovered {
+		if !listed[filepath.ToSlash(rel)] {
+			extras = append(extras, rel)
+		}
+	}
+	if len(extras) > 0 {
+		fmt.Fprintf(stderr, "\nnot in manifest:\n")
+		for _, rel := range extras {
+			fmt.Fprintf(stderr, "  %s\n", rel)
+		}
+	}
+
+	if len(failures) > 0 {
+		printFailures(stderr, failures)
+		return fmt.Errorf("could not restore %d repos or worktrees", len(failures))
+	}
+	return nil
+}
+
+// restoreWorktrees creates the worktrees listed in m for branches that have
+// none yet, in repos that are cloned. The post-wkt-new hook runs only the
+// configured commands, never scripts committed to repos that may have just
+// been cloned, and not at all with noHooks. It returns the failures.
+func restoreWorktrees(m *manifest.Manifest, srcRoot string, noHooks bool, stderr io.Writer) []string {
+	var failures []string
+	for _, r := range m.Repos {
+		if len(r.Worktrees) == 0 {
+			continue
+		}
+		rp, _ := r.Path()
+		dir := filepath.Join(srcRoot, rp.FullPath())
+		if exists, _ := checkCloneTarget(dir); !exists {
+			continue
+		}
+
+		info := &worktree.RepoInfo{MainPath: dir, CurrentPath: dir, Source: rp.Source, Org: rp.Org, Repo: rp.Project}
+		wts, err := worktree.ListWorktrees(info)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", rp.FullPath(), err))
+			continue
+		}
+		for _, branch := range r.Worktrees {
+			if slices.ContainsFunc(wts, func(wt worktree.Worktree) bool { return wt.Branch == branch }) {
+				continue
+			}
+			path, err := worktree.CreateWorktree(info, branch, worktree.CreateOptions{SkipHooks: noHooks, IgnoreCommittedHooks: true})
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: worktree %s: %v", rp.FullPath(), branch, err))
+				continue
+			}
+			fmt.Fprintf(stderr, "created worktree %s\n", path)
+		}
+	}
+	return failures
+}
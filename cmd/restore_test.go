@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/hooks"
+	"github.com/dsaiztc/dev/internal/manifest"
+)
+
+// setupRemotes isolates HOME and git config and makes https://example.com/
+// URLs resolve to upstream repos created under the returned directory.
+func setupRemotes(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("DEV_SRC_ROOT", "")
+	remotes := t.TempDir()
+	gitconfig := filepath.Join(home, ".gitconfig")
+	content := "[user]\n\tname = Test\n\temail = test@example.com\n" +
+		"[url \"file://" + remotes + "/\"]\n\tinsteadOf = https://example.com/\n"
+	if err := os.WriteFile(gitconfig, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	return remotes
+}
+
+// initUpstream creates a repo with one commit on main and the given branches.
+func initUpstream(t *testing.T, dir string, branches ...string) {
+	t.Helper()
+	cmds := [][]string{
+		{"init", "-q", "-b", "main", dir},
+		{"-C", dir, "commit", "-q", "--allow-empty", "-m", "init"},
+	}
+	for _, b := range branches {
+		cmds = append(cmds, []string{"-C", dir, "branch", b})
+	}
+	for _, args := range cmds {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+// gitOutput runs git in dir and returns its stdout.
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return string(out)
+}
+
+func TestRestoreManifest(t *testing.T) {
+	remotes := setupRemotes(t)
+	initUpstream(t, filepath.Join(remotes, "mycompany", "api.git"), "release")
+	initUpstream(t, filepath.Join(remotes, "mycompany", "web.git"), "feature-x")
+
+	srcRoot := filepath.Join(os.Getenv("HOME"), "src")
+	extra := filepath.Join(srcRoot, "github.com", "me", "scratch")
+	if out, err := exec.Command("git", "init", "-q", extra).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	m := &manifest.Manifest{Repos: []manifest.Repo{
+		{URL: "https://example.com/mycompany/api.git", Branch: "release"},
+		{URL: "https://example.com/mycompany/web.git", Worktrees: []string{"feature-x"}},
+		{URL: "https://example.com/mycompany/gone.git"},
+	}}
+
+	var stdout, stderr bytes.Buffer
+	if err := restoreManifest(m, srcRoot, 2, true, true, &stdout, &stderr); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want := "example.com/mycompany/api\nexample.com/mycompany/web\nexample.com/mycompany/gone\n"
+	if stdout.String() != want {
+		t.Errorf("dry run stdout = %q, want %q", stdout.String(), want)
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	err := restoreManifest(m, srcRoot, 2, true, false, &stdout, &stderr)
+	if err == nil || err.Error() != "could not restore 1 repos or worktrees" {
+		t.Errorf("restoreManifest error = %v, want one failure", err)
+	}
+
+	api := filepath.Join(srcRoot, "example.com", "mycompany", "api")
+	if got := strings.TrimSpace(gitOutput(t, api, "branch", "--show-current")); got != "release" {
+		t.Errorf("api branch = %q, want release", got)
+	}
+	wt := filepath.Join(os.Getenv("HOME"), "src__worktrees", "example.com", "mycompany", "web__feature-x")
+	if got := strings.TrimSpace(gitOutput(t, wt, "branch", "--show-current")); got != "feature-x" {
+		t.Errorf("worktree branch = %q, want feature-x", got)
+	}
+
+	out := stderr.String()
+	for _, s := range []string{
+		"3 repos in manifest, 3 to clone",
+		"cloned 2 repos",
+		"created worktree " + wt,
+		"not in manifest:\n  github.com/me/scratch\n",
+		"failed:\n  example.com/mycompany/gone: git clone failed",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("stderr missing %q:\n%s", s, out)
+		}
+	}
+
+	// A second run has nothing left to do
+	stderr.Reset()
+	m.Repos = m.Repos[:2]
+	if err := restoreManifest(m, srcRoot, 2, true, false, &stdout, &stderr); err != nil {
+		t.Fatalf("second restore: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "2 repos in manifest, 0 to clone") || strings.Contains(stderr.String(), "created worktree") {
+		t.Errorf("second restore did work again:\n%s", stderr.String())
+	}
+}
+
+func TestBuildManifest(t *testing.T) {
+	remotes := setupRemotes(t)
+	initUpstream(t, filepath.Join(remotes, "mycompany", "api.git"), "feature-x")
+
+	srcRoot := filepath.Join(os.Getenv("HOME"), "src")
+	api := filepath.Join(srcRoot, "example.com", "mycompany", "api")
+	if out, err := exec.Command("git", "clone", "-q", "https://example.com/mycompany/api.git", api).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+	if out, err := exec.Command("git", "-C", api, "worktree", "add", "-q", filepath.Join(t.TempDir(), "fx"), "feature-x").CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, out)
+	}
+	local := filepath.Join(srcRoot, "github.com", "me", "local")
+	if out, err := exec.Command("git", "init", "-q", local).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	var stderr bytes.Buffer
+	m := buildManifest(srcRoot, []string{"example.com/mycompany/api", "github.com/me/local"}, true, &stderr)
+	if len(m.Repos) != 1 {
+		t.Fatalf("manifest = %+v, want only the repo with an origin", m.Repos)
+	}
+	r := m.Repos[0]
+	if r.URL != "https://example.com/mycompany/api.git" || strings.Join(r.Worktrees, ",") != "feature-x" {
+		t.Errorf("repo = %+v", r)
+	}
+	if !strings.Contains(stderr.String(), "skipping github.com/me/local: no origin remote") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRestoreWorktrees_Hooks(t *testing.T) {
+	remotes := setupRemotes(t)
+	upstream := filepath.Join(remotes, "mycompany", "api.git")
+	initUpstream(t, upstream)
+	hookScript := filepath.Join(upstream, ".dev", "hooks", "post-wkt-new")
+	if err := os.MkdirAll(filepath.Dir(hookScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hookScript, []byte("#!/bin/sh\ntouch script-ran\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, upstream, "add", ".dev")
+	gitOutput(t, upstream, "commit", "-q", "-m", "add hook")
+	gitOutput(t, upstream, "branch", "feature-x")
+	gitOutput(t, upstream, "branch", "feature-y")
+
+	// Even a trusted repo's committed scripts are skipped on restore
+	cfg := &config.Config{Repos: map[string]config.RepoConfig{
+		"example.com/mycompany/api": {TrustCommittedHooks: true, Hooks: config.Hooks{hooks.PostWktNew: {"touch config-ran"}}},
+	}}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+	srcRoot := filepath.Join(os.Getenv("HOME"), "src")
+	if out, err := exec.Command("git", "clone", "-q", "https://example.com/mycompany/api.git", filepath.Join(srcRoot, "example.com", "mycompany", "api")).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+
+	wtRoot := filepath.Join(os.Getenv("HOME"), "src__worktrees", "example.com", "mycompany")
+	ran := func(branch, marker string) bool {
+		_, err := os.Stat(filepath.Join(wtRoot, "api__"+branch, marker))
+		return err == nil
+	}
+
+	m := &manifest.Manifest{Repos: []manifest.Repo{{URL: "https://example.com/mycompany/api.git", Worktrees: []string{"feature-x"}}}}
+	if failures := restoreWorktrees(m, srcRoot, false, &bytes.Buffer{}); len(failures) > 0 {
+		t.Fatalf("restoreWorktrees: %v", failures)
+	}
+	if ran("feature-x", "script-ran") || !ran("feature-x", "config-ran") {
+		t.Error("want only the configured post-wkt-new command to run")
+	}
+
+	m.Repos[0].Worktrees = []string{"feature-y"}
+	if failures := restoreWorktrees(m, srcRoot, true, &bytes.Buffer{}); len(failures) > 0 {
+		t.Fatalf("restoreWorktrees: %v", failures)
+	}
+	if ran("feature-y", "script-ran") || ran("feature-y", "config-ran") {
+		t.Error("no hooks should run with --no-hooks")
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the git status of every repo",
+	Long: `Shows the branch, changed and untracked file counts, ahead/behind counts
+versus upstream, stash count and last commit age of every repo under the
+source root, inspecting repos concurrently.
+
+Repos can be selected with --filter globs over source/org/repo (a glob also
+matches everything below it, e.g. github.com/mycompany), a fuzzy --query or a
+--group from the config, and narrowed down with --dirty, --ahead and --behind.`,
+	Args: cobra.NoArgs,
+	RunE: runStatus,
+}
+
+// statusSortKeys lists the columns dev status can sort by.
+var statusSortKeys = []string{"repo", "branch", "changed", "untracked", "ahead", "behind", "stashes", "age"}
+
+func init() {
+	statusCmd.Flags().Bool("json", false, "print JSON instead of a table")
+	addSelectionFlags(statusCmd)
+	statusCmd.Flags().Bool("dirty", false, "only repos with changed or untracked files")
+	statusCmd.Flags().Bool("ahead", false, "only repos with commits not pushed upstream")
+	statusCmd.Flags().Bool("behind", false, "only repos behind their upstream")
+	statusCmd.Flags().String("sort", "repo", "sort by "+strings.Join(statusSortKeys, ", "))
+	statusCmd.Flags().Int("parallel", pool.DefaultWorkers(), "number of repos inspected concurrently")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// repoStatus is the state of one repo under the source root.
+type repoStatus struct {
+	worktree.Status
+	Stashes int `json:"stashes"`
+}
+
+// statusFilter selects which repos dev status shows.
+type statusFilter struct {
+	dirty, ahead, behind bool
+}
+
+func (f statusFilter) matches(st repoStatus) bool {
+	return (!f.dirty || st.Dirty) && (!f.ahead || st.Ahead > 0) && (!f.behind || st.Behind > 0)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	sortKey, _ := cmd.Flags().GetString("sort")
+	if !slices.Contains(statusSortKeys, sortKey) {
+		return fmt.Errorf("invalid --sort %q (valid: %s)", sortKey, strings.Join(statusSortKeys, ", "))
+	}
+
+	srcRoot, repoPaths, err := selectedRepos(cmd)
+	if err != nil {
+		return err
+	}
+
+	workers, _ := cmd.Flags().GetInt("parallel")
+	statuses := collectStatuses(srcRoot, repoPaths, workers)
+
+	var filter statusFilter
+	filter.dirty, _ = cmd.Flags().GetBool("dirty")
+	filter.ahead, _ = cmd.Flags().GetBool("ahead")
+	filter.behind, _ = cmd.Flags().GetBool("behind")
+	shown := []repoStatus{}
+	for _, st := range statuses {
+		if filter.matches(st) {
+			shown = append(shown, st)
+		}
+	}
+	sortStatuses(shown, sortKey)
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+	if len(shown) == 0 {
+		fmt.Fprintln(os.Stderr, "no matching repos")
+		return nil
+	}
+	printStatusTable(os.Stdout, shown, time.Now())
+	return nil
+}
+
+// collectStatuses inspects the repos at repoPaths (relative to srcRoot),
+// running at most workers inspections at once.
+func collectStatuses(srcRoot string, repoPaths []string, workers int) []repoStatus {
+	statuses := make([]repoStatus, len(repoPaths))
+	pool.Each(repoPaths, workers, func(i int, rel string) {
+		dir := filepath.Join(srcRoot, rel)
+		st := repoStatus{Status: worktree.GetStatus(worktree.Worktree{Path: dir, IsMain: true})}
+		st.Repo = filepath.ToSlash(rel)
+		st.Stashes = stashCount(dir)
+		statuses[i] = st
+	})
+	return statuses
+}
+
+// stashCount returns the number of stash entries of the repo at dir.
+func stashCount(dir string) int {
+	cmd := exec.Command("git", "stash", "list", "--format=%gd")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return 0
+	}
+	return strings.Count(string(out), "\n")
+}
+
+// sortStatuses sorts statuses by key, largest counts and oldest commits
+// first, falling back to the repo path.
+func sortStatuses(statuses []repoStatus, key string) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		a, b := statuses[i], statuses[j]
+		var less, greater bool
+		switch key {
+		case "branch":
+			less, greater = a.Branch < b.Branch, a.Branch > b.Branch
+		case "changed":
+			less, greater = a.Changed > b.Changed, a.Changed < b.Changed
+		case "untracked":
+			less, greater = a.Untracked > b.Untracked, a.Untracked < b.Untracked
+		case "ahead":
+			less, greater = a.Ahead > b.Ahead, a.Ahead < b.Ahead
+		case "behind":
+			less, greater = a.Behind > b.Behind, a.Behind < b.Behind
+		case "stashes":
+			less, greater = a.Stashes > b.Stashes, a.Stashes < b.Stashes
+		case "age":
+			less, greater = a.LastCommit.Before(b.LastCommit), a.LastCommit.After(b.LastCommit)
+		}
+		if less || greater {
+			return less
+		}
+		return a.Repo < b.Repo
+	})
+}
+
+// printStatusTable renders statuses as an aligned table. Zero counts are
+// shown as "-" so the repos needing attention stand out.
+func printStatusTable(w io.Writer, statuses []repoStatus, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tBRANCH\tCHANGED\tUNTRACKED\tUPSTREAM\tSTASHES\tAGE")
+	for _, st := range statuses {
+		branch := st.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		age := "-"
+		if !st.LastCommit.IsZero() {
+			age = formatAge(now.Sub(st.LastCommit))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			st.Repo, branch, formatCount(st.Changed), formatCount(st.Untracked), formatSync(st.Status), formatCount(st.Stashes), age)
+	}
+	tw.Flush()
+}
+
+// formatCount renders n, or "-" for zero.
+func formatCount(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/worktree"
+)
+
+func TestSortStatuses(t *testing.T) {
+	now := time.Now()
+	statuses := []repoStatus{
+		{Status: worktree.Status{Repo: "c", Changed: 1, LastCommit: now}},
+		{Status: worktree.Status{Repo: "a", Ahead: 2, LastCommit: now.Add(-time.Hour)}},
+		{Status: worktree.Status{Repo: "b", Changed: 5}, Stashes: 1},
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"repo", "a,b,c"},
+		{"changed", "b,c,a"},
+		{"ahead", "a,b,c"},
+		{"stashes", "b,a,c"},
+		{"age", "b,a,c"},
+	}
+	for _, tt := range tests {
+		sortStatuses(statuses, tt.key)
+		var got []string
+		for _, st := range statuses {
+			got = append(got, st.Repo)
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("sort by %s = %v, want %s", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestStatusFilter(t *testing.T) {
+	clean := repoStatus{}
+	dirty := repoStatus{Status: worktree.Status{Dirty: true, Changed: 1}}
+	ahead := repoStatus{Status: worktree.Status{Ahead: 1, Upstream: "origin/main"}}
+
+	if !(statusFilter{}).matches(clean) {
+		t.Error("an empty filter should match everything")
+	}
+	if (statusFilter{dirty: true}).matches(clean) || !(statusFilter{dirty: true}).matches(dirty) {
+		t.Error("--dirty should only match dirty repos")
+	}
+	if (statusFilter{ahead: true}).matches(dirty) || !(statusFilter{ahead: true}).matches(ahead) {
+		t.Error("--ahead should only match repos with unpushed commits")
+	}
+	if (statusFilter{dirty: true, ahead: true}).matches(ahead) {
+		t.Error("filters should combine")
+	}
+}
+
+func TestPrintStatusTable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	statuses := []repoStatus{
+		{Status: worktree.Status{Repo: "github.com/me/api", Branch: "main", Upstream: "origin/main", Behind: 3, LastCommit: now.Add(-2 * time.Hour)}},
+		{Status: worktree.Status{Repo: "github.com/me/web", Changed: 2, Untracked: 1}, Stashes: 4},
+	}
+	var buf bytes.Buffer
+	printStatusTable(&buf, statuses, now)
+
+	want := "REPO               BRANCH      CHANGED  UNTRACKED  UPSTREAM  STASHES  AGE\n" +
+		"github.com/me/api  main        -        -          ↓3        -        2h\n" +
+		"github.com/me/web  (detached)  2        1          -         4        -\n"
+	if buf.String() != want {
+		t.Errorf("table =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestCollectStatuses(t *testing.T) {
+	remotes := setupRemotes(t)
+	initUpstream(t, filepath.Join(remotes, "me", "api.git"))
+	srcRoot := t.TempDir()
+	dir := filepath.Join(srcRoot, "example.com", "me", "api")
+	if out, err := exec.Command("git", "clone", "-q", "https://example.com/me/api.git", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One stash, two unpushed commits, one changed and one untracked file
+	write("stashed.txt", "x")
+	git("stash", "push", "-q", "--include-untracked")
+	git("commit", "-q", "--allow-empty", "-m", "wip")
+	write("tracked.txt", "x")
+	git("add", "tracked.txt")
+	git("commit", "-q", "-m", "add tracked")
+	write("tracked.txt", "changed")
+	write("new.txt", "x")
+
+	statuses := collectStatuses(srcRoot, []string{"example.com/me/api"}, 2)
+	if len(statuses) != 1 {
+		t.Fatalf("got %d statuses, want 1", len(statuses))
+	}
+	st := statuses[0]
+	if st.Repo != "example.com/me/api" || st.Branch != "main" || st.Changed != 1 || st.Untracked != 1 ||
+		st.Ahead != 2 || st.Behind != 0 || st.Stashes != 1 || st.Upstream != "origin/main" {
+		t.Errorf("status = %+v", st)
+	}
+}
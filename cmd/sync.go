@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/pool"
+	"github.com/dsaiztc/dev/internal/progress"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Fetch every repo and fast-forward default branches",
+	Long: `Fetches every repo under the source root in parallel, each with a timeout.
+
+With --pull, also fast-forwards the default branch of repos that have it
+checked out, as long as the working tree is clean and the branch has no
+commits of its own. Nothing is ever merged or rebased: repos with local
+changes are skipped, and branches that diverged from upstream are reported.`,
+	Args: cobra.NoArgs,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().Bool("pull", false, "fast-forward the checked-out default branch of clean repos")
+	addSelectionFlags(syncCmd)
+	syncCmd.Flags().Int("parallel", pool.DefaultWorkers(), "number of repos synced concurrently")
+	syncCmd.Flags().Duration("timeout", 2*time.Minute, "time limit for each repo")
+	summarizesFailures(syncCmd)
+	rootCmd.AddCommand(syncCmd)
+}
+
+// Sync outcomes.
+const (
+	syncFetched  = "fetched"
+	syncUpdated  = "updated"  // default branch fast-forwarded
+	syncDirty    = "dirty"    // behind, but local changes prevented the fast-forward
+	syncDiverged = "diverged" // checked-out branch has both local and upstream commits
+	syncNoRemote = "no remote"
+	syncFailed   = "failed"
+)
+
+// syncResult is the outcome of syncing one repo.
+type syncResult struct {
+	repo    string
+	outcome string
+	detail  string // e.g. the branch and counts, or the error for failures
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	srcRoot, repoPaths, err := selectedRepos(cmd)
+	if err != nil {
+		return err
+	}
+
+	pull, _ := cmd.Flags().GetBool("pull")
+	workers, _ := cmd.Flags().GetInt("parallel")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	results := syncRepos(cmd.Context(), srcRoot, repoPaths, pull, workers, timeout, os.Stderr)
+	return summarizeSync(os.Stderr, results)
+}
+
+// syncRepos syncs the repos at repoPaths (relative to srcRoot), running at
+// most workers at once, each limited to timeout. Progress goes to stderr.
+func syncRepos(ctx context.Context, srcRoot string, repoPaths []string, pull bool, workers int, timeout time.Duration, stderr io.Writer) []syncResult {
+	results := make([]syncResult, len(repoPaths))
+	tracker := progress.Start(stderr, "syncing", len(repoPaths))
+	pool.Each(repoPaths, workers, func(i int, rel string) {
+		tracker.Begin(rel)
+		repoCtx, cancel := context.WithTimeout(ctx, timeout)
+		results[i] = syncRepo(repoCtx, filepath.Join(srcRoot, rel), pull)
+		cancel()
+		if errors.Is(repoCtx.Err(), context.DeadlineExceeded) && results[i].outcome == syncFailed {
+			results[i].detail = fmt.Sprintf("timed out after %s", timeout)
+		}
+		results[i].repo = filepath.ToSlash(rel)
+		tracker.Done(results[i].repo, results[i].outcome)
+	})
+	tracker.Stop()
+	return results
+}
+
+// syncRepo fetches the repo at dir and, with pull, fast-forwards its default
+// branch if it is checked out, clean and strictly behind upstream.
+func syncRepo(ctx context.Context, dir string, pull bool) syncResult {
+	remotes, err := syncGit(ctx, dir, "remote")
+	if err != nil {
+		return syncResult{outcome: syncFailed, detail: err.Error()}
+	}
+	if remotes == "" {
+		return syncResult{outcome: syncNoRemote}
+	}
+	if _, err := syncGit(ctx, dir, "fetch", "--quiet", "--prune"); err != nil {
+		return syncResult{outcome: syncFailed, detail: err.Error()}
+	}
+
+	st := worktree.GetStatus(worktree.Worktree{Path: dir, IsMain: true})
+	position := fmt.Sprintf("%s %s", st.Branch, formatSync(st))
+	switch {
+	case st.Ahead > 0 && st.Behind > 0:
+		return syncResult{outcome: syncDiverged, detail: position}
+	case !pull || st.Behind == 0:
+		return syncResult{outcome: syncFetched}
+	}
+
+	def, ok := strings.CutPrefix(worktree.DefaultBranch(&worktree.RepoInfo{MainPath: dir}), "origin/")
+	switch {
+	case !ok || st.Branch != def || st.Ahead > 0:
+		// Only the default branch is updated, and only when it has nothing to lose
+		return syncResult{outcome: syncFetched}
+	case st.Dirty:
+		return syncResult{outcome: syncDirty, detail: position}
+	}
+	if _, err := syncGit(ctx, dir, "merge", "--ff-only", "--quiet", "@{upstream}"); err != nil {
+		return syncResult{outcome: syncFailed, detail: err.Error()}
+	}
+	return syncResult{outcome: syncUpdated, detail: position}
+}
+
+// syncGit runs git non-interactively in dir, returning its trimmed stdout, or
+// an error carrying its stderr.
+func syncGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	// Don't wait forever for ssh or credential helpers that outlive a killed git
+	cmd.WaitDelay = 5 * time.Second
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// summarizeSync prints the outcome counts and lists the repos that need
+// attention. It fails if any repo could not be synced.
+func summarizeSync(w io.Writer, results []syncResult) error {
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.outcome]++
+	}
+	var parts []string
+	for _, outcome := range []string{syncFetched, syncUpdated, syncDirty, syncDiverged, syncNoRemote, syncFailed} {
+		if counts[outcome] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[outcome], outcome))
+		}
+	}
+	if len(parts) == 0 {
+		fmt.Fprintln(w, "no repos to sync")
+		return nil
+	}
+	fmt.Fprintf(w, "synced %d repos: %s\n", len(results), strings.Join(parts, ", "))
+
+	for _, section := range []struct{ outcome, heading string }{
+		{syncUpdated, "fast-forwarded"},
+		{syncDirty, "skipped, local changes"},
+		{syncDiverged, "diverged from upstream"},
+		{syncFailed, "failed"},
+	} {
+		if counts[section.outcome] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", section.heading)
+		for _, r := range results {
+			if r.outcome == section.outcome {
+				fmt.Fprintf(w, "  %s: %s\n", r.repo, r.detail)
+			}
+		}
+	}
+
+	if counts[syncFailed] > 0 {
+		return fmt.Errorf("could not sync %d of %d repos", counts[syncFailed], len(results))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syncFixture returns an upstream repo and a source root holding clones of it
+// under example.com/me/<name> for each of names.
+func syncFixture(t *testing.T, names ...string) (upstream, srcRoot string) {
+	t.Helper()
+	remotes := setupRemotes(t)
+	upstream = filepath.Join(remotes, "me", "api.git")
+	initUpstream(t, upstream)
+	srcRoot = t.TempDir()
+	for _, name := range names {
+		dir := filepath.Join(srcRoot, "example.com", "me", name)
+		if out, err := exec.Command("git", "clone", "-q", "https://example.com/me/api.git", dir).CombinedOutput(); err != nil {
+			t.Fatalf("git clone: %v\n%s", err, out)
+		}
+	}
+	return upstream, srcRoot
+}
+
+// commitIn makes an empty commit in dir.
+func commitIn(t *testing.T, dir, msg string) {
+	t.Helper()
+	if out, err := exec.Command("git", "-C", dir, "commit", "-q", "--allow-empty", "-m", msg).CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+}
+
+func TestSyncRepo(t *testing.T) {
+	upstream, srcRoot := syncFixture(t, "clean", "fetch-only", "dirty", "diverged", "feature")
+	repo := func(name string) string { return filepath.Join(srcRoot, "example.com", "me", name) }
+
+	os.WriteFile(filepath.Join(repo("dirty"), "wip.txt"), []byte("x"), 0o644)
+	commitIn(t, repo("diverged"), "local")
+	if out, err := exec.Command("git", "-C", repo("feature"), "checkout", "-q", "-b", "feature", "--track", "origin/main").CombinedOutput(); err != nil {
+		t.Fatalf("git checkout: %v\n%s", err, out)
+	}
+	commitIn(t, upstream, "upstream")
+	want := strings.TrimSpace(gitOutput(t, upstream, "rev-parse", "HEAD"))
+
+	tests := []struct {
+		name    string
+		pull    bool
+		outcome string
+		head    string // expected HEAD after syncing, if not the original one
+	}{
+		{name: "clean", pull: true, outcome: syncUpdated, head: want},
+		{name: "fetch-only", pull: false, outcome: syncFetched},
+		{name: "dirty", pull: true, outcome: syncDirty},
+		{name: "diverged", pull: true, outcome: syncDiverged},
+		{name: "feature", pull: true, outcome: syncFetched},
+	}
+	for _, tt := range tests {
+		dir := repo(tt.name)
+		before := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "HEAD"))
+		got := syncRepo(context.Background(), dir, tt.pull)
+		if got.outcome != tt.outcome {
+			t.Errorf("%s: outcome = %q (%s), want %q", tt.name, got.outcome, got.detail, tt.outcome)
+		}
+		head := strings.TrimSpace(gitOutput(t, dir, "rev-parse", "HEAD"))
+		if wantHead := cmp.Or(tt.head, before); head != wantHead {
+			t.Errorf("%s: HEAD = %s, want %s", tt.name, head, wantHead)
+		}
+	}
+	if behind := gitOutput(t, repo("fetch-only"), "rev-list", "--count", "HEAD..origin/main"); strings.TrimSpace(behind) != "1" {
+		t.Errorf("fetch-only: origin/main was not fetched")
+	}
+}
+
+func TestSyncRepos_FailuresAndTimeouts(t *testing.T) {
+	_, srcRoot := syncFixture(t, "gone")
+	if out, err := exec.Command("git", "-C", filepath.Join(srcRoot, "example.com", "me", "gone"), "remote", "set-url", "origin", "https://example.com/me/missing.git").CombinedOutput(); err != nil {
+		t.Fatalf("git remote set-url: %v\n%s", err, out)
+	}
+	local := filepath.Join(srcRoot, "example.com", "me", "local")
+	if out, err := exec.Command("git", "init", "-q", local).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	var stderr bytes.Buffer
+	results := syncRepos(context.Background(), srcRoot, []string{"example.com/me/gone", "example.com/me/local"}, true, 2, time.Minute, &stderr)
+	if results[0].outcome != syncFailed || !strings.Contains(results[0].detail, "git fetch") {
+		t.Errorf("gone = %+v, want a failed fetch", results[0])
+	}
+	if results[1].outcome != syncNoRemote {
+		t.Errorf("local = %+v, want no remote", results[1])
+	}
+	if !strings.Contains(stderr.String(), "failed example.com/me/gone") {
+		t.Errorf("progress = %q", stderr.String())
+	}
+
+	results = syncRepos(context.Background(), srcRoot, []string{"example.com/me/gone"}, false, 1, time.Nanosecond, &stderr)
+	if results[0].outcome != syncFailed || results[0].detail != "timed out after 1ns" {
+		t.Errorf("result = %+v, want a timeout", results[0])
+	}
+}
+
+func TestSummarizeSync(t *testing.T) {
+	results := []syncResult{
+		{repo: "a", outcome: syncFetched},
+		{repo: "b", outcome: syncUpdated, detail: "main ↓2"},
+		{repo: "c", outcome: syncDirty, detail: "main ↓1"},
+		{repo: "d", outcome: syncDiverged, detail: "feat ↑1 ↓1"},
+		{repo: "e", outcome: syncFailed, detail: "git fetch: fatal: nope"},
+	}
+	var buf bytes.Buffer
+	err := summarizeSync(&buf, results)
+	if err == nil || err.Error() != "could not sync 1 of 5 repos" {
+		t.Errorf("error = %v", err)
+	}
+	want := "synced 5 repos: 1 fetched, 1 updated, 1 dirty, 1 diverged, 1 failed\n" +
+		"\nfast-forwarded:\n  b: main ↓2\n" +
+		"\nskipped, local changes:\n  c: main ↓1\n" +
+		"\ndiverged from upstream:\n  d: feat ↑1 ↓1\n" +
+		"\nfailed:\n  e: git fetch: fatal: nope\n"
+	if buf.String() != want {
+		t.Errorf("summary =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := summarizeSync(&buf, []syncResult{{repo: "a", outcome: syncFetched}}); err != nil || buf.String() != "synced 1 repos: 1 fetched\n" {
+		t.Errorf("summary = %q, %v", buf.String(), err)
+	}
+}
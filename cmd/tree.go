@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dsaiztc/dev/internal/config"
 	"github.com/dsaiztc/dev/internal/repos"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +15,7 @@ import (
 var treeCmd = &cobra.Command{
 	Use:   "tree",
 	Short: "Display a tree view of all repositories",
-	Long:  `Shows the directory structure from ~/src/ down to each repository.`,
+	Long:  `Shows the directory structure from the source root (~/src/ by default) down to each repository.`,
 	RunE:  runTree,
 }
 
@@ -23,35 +24,45 @@ func init() {
 }
 
 func runTree(cmd *cobra.Command, args []string) error {
-	// 1. Get home directory
-	homeDir, err := os.UserHomeDir()
+	// 1. Resolve source root
+	baseDir, err := config.SrcRoot()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
+		return err
 	}
 
-	// 2. Build base path
-	baseDir := filepath.Join(homeDir, "src")
-
-	// 3. Discover repos
-	allRepos, err := repos.Discover(baseDir)
-	if err != nil {
-		return fmt.Errorf("could not discover repos: %w", err)
-	}
+	// 2. Discover repos
+	allRepos := repos.DiscoverCached(baseDir)
 
-	// 4. Handle empty case
+	// 3. Handle empty case
 	if len(allRepos) == 0 {
 		fmt.Fprintf(os.Stderr, "no repos found under %s\n", baseDir)
 		return nil
 	}
 
-	// 5. Build and render tree
+	// 4. Build and render tree
 	root := buildTree(allRepos)
-	fmt.Printf("~/src/\n")
+	fmt.Printf("%s/\n", displayRoot(baseDir))
 	printTree(root, "", false)
 
 	return nil
 }
 
+// displayRoot abbreviates the user's home directory in root as ~.
+func displayRoot(root string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return root
+	}
+	rel, err := filepath.Rel(homeDir, root)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return root
+	}
+	if rel == "." {
+		return "~"
+	}
+	return filepath.Join("~", rel)
+}
+
 // treeNode represents a node in the directory tree
 type treeNode struct {
 	name     string
@@ -3,16 +3,22 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/dsaiztc/dev/internal/fuzzy"
+	"github.com/dsaiztc/dev/internal/history"
 	"github.com/dsaiztc/dev/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
 var wktCdCmd = &cobra.Command{
-	Use:   "cd",
-	Short: "Navigate to a worktree via fuzzy finder",
-	RunE:  runWktCd,
+	Use:               "cd [branch]",
+	Short:             "Navigate to a worktree via fuzzy finder",
+	Long:              `Without arguments, opens a fuzzy finder over the worktrees of the current repo. With a branch, jumps to its worktree (exact match first, then substring).`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorktreeBranches(true),
+	RunE:              runWktCd,
 }
 
 func init() {
@@ -34,6 +40,31 @@ func runWktCd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no worktrees found")
 	}
 
+	if len(args) == 1 {
+		query := args[0]
+		for _, wt := range worktrees {
+			if wt.Branch == query {
+				return cdToWorktree(wt.Branch, wt.Path)
+			}
+		}
+		for _, wt := range worktrees {
+			if strings.Contains(wt.Branch, query) {
+				return cdToWorktree(wt.Branch, wt.Path)
+			}
+		}
+		return fmt.Errorf("no worktree matching %q", query)
+	}
+
+	// Most frecent worktrees first
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+	scores := history.Scores(paths)
+	sort.SliceStable(worktrees, func(i, j int) bool {
+		return scores[worktrees[i].Path] > scores[worktrees[j].Path]
+	})
+
 	// Build display items: branch name with (main) annotation
 	items := make([]string, len(worktrees))
 	pathMap := make(map[string]string, len(worktrees))
@@ -54,8 +85,12 @@ func runWktCd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	path := pathMap[selected]
-	fmt.Fprintf(os.Stderr, "%s\n", selected)
-	fmt.Printf("cd %s\n", path)
-	return nil
+	return cdToWorktree(selected, pathMap[selected])
+}
+
+// cdToWorktree records the visit and asks the shell wrapper to cd into path.
+func cdToWorktree(label, path string) error {
+	_ = history.Visit(path)
+	fmt.Fprintf(os.Stderr, "%s\n", label)
+	return emitCD(os.Stdout, path)
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var wktLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List worktrees with their status",
+	Long: `Lists every worktree of the current repo with its branch, short commit,
+dirty/clean state, ahead/behind counts versus upstream, last commit age and path.
+
+With --all, lists the worktrees of every repo that has linked worktrees under
+the worktree root.`,
+	Args: cobra.NoArgs,
+	RunE: runWktLs,
+}
+
+func init() {
+	wktLsCmd.Flags().Bool("json", false, "print JSON instead of a table")
+	wktLsCmd.Flags().Bool("all", false, "list worktrees of every repo under the worktree root")
+	wktCmd.AddCommand(wktLsCmd)
+}
+
+func runWktLs(cmd *cobra.Command, args []string) error {
+	asJSON, _ := cmd.Flags().GetBool("json")
+	all, _ := cmd.Flags().GetBool("all")
+
+	repoInfos, err := worktreeRepos(all)
+	if err != nil {
+		return err
+	}
+
+	var statuses []worktree.Status
+	for _, repoInfo := range repoInfos {
+		repoStatuses, err := worktree.ListStatuses(repoInfo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v\n", repoInfo.MainPath, err)
+			continue
+		}
+		if all {
+			for i := range repoStatuses {
+				repoStatuses[i].Repo = repoInfo.Name()
+			}
+		}
+		statuses = append(statuses, repoStatuses...)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if statuses == nil {
+			statuses = []worktree.Status{}
+		}
+		return enc.Encode(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Fprintln(os.Stderr, "no worktrees found")
+		return nil
+	}
+	printWorktreeTable(os.Stdout, statuses, all, time.Now())
+	return nil
+}
+
+// worktreeRepos returns the current repo, or with all every repo that has
+// linked worktrees under the worktree root.
+func worktreeRepos(all bool) ([]*worktree.RepoInfo, error) {
+	if !all {
+		repoInfo, err := worktree.DetectCurrentRepo()
+		if err != nil {
+			return nil, err
+		}
+		return []*worktree.RepoInfo{repoInfo}, nil
+	}
+
+	root, err := worktree.GetWorktreeRoot()
+	if err != nil {
+		return nil, err
+	}
+	srcRoot, err := config.SrcRoot()
+	if err != nil {
+		return nil, err
+	}
+	return worktree.DiscoverWorktreeRepos(root, srcRoot), nil
+}
+
+// printWorktreeTable renders statuses as an aligned table.
+func printWorktreeTable(w io.Writer, statuses []worktree.Status, withRepo bool, now time.Time) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if withRepo {
+		fmt.Fprint(tw, "REPO\t")
+	}
+	fmt.Fprintln(tw, "BRANCH\tCOMMIT\tSTATE\tUPSTREAM\tAGE\tPATH")
+
+	for _, st := range statuses {
+		branch := st.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		if st.IsMain {
+			branch += " (main)"
+		}
+
+		state := "clean"
+		switch {
+		case st.Missing:
+			state = "missing"
+		case st.Dirty:
+			state = "dirty"
+		}
+
+		age := "-"
+		if !st.LastCommit.IsZero() {
+			age = formatAge(now.Sub(st.LastCommit))
+		}
+
+		if withRepo {
+			fmt.Fprintf(tw, "%s\t", st.Repo)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", branch, st.ShortCommit(), state, formatSync(st), age, st.Path)
+	}
+	tw.Flush()
+}
+
+// formatSync describes a worktree's position relative to its upstream.
+func formatSync(st worktree.Status) string {
+	switch {
+	case st.Upstream == "":
+		return "-"
+	case st.Ahead == 0 && st.Behind == 0:
+		return "up to date"
+	case st.Behind == 0:
+		return fmt.Sprintf("↑%d", st.Ahead)
+	case st.Ahead == 0:
+		return fmt.Sprintf("↓%d", st.Behind)
+	}
+	return fmt.Sprintf("↑%d ↓%d", st.Ahead, st.Behind)
+}
+
+// formatAge renders a duration in its largest whole unit (e.g. "3d", "5h").
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d.Hours()/(24*7)))
+	}
+	return fmt.Sprintf("%dy", int(d.Hours()/(24*365)))
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/worktree"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "now"},
+		{5 * time.Minute, "5m"},
+		{3 * time.Hour, "3h"},
+		{50 * time.Hour, "2d"},
+		{15 * 24 * time.Hour, "2w"},
+		{800 * 24 * time.Hour, "2y"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSync(t *testing.T) {
+	tests := []struct {
+		st   worktree.Status
+		want string
+	}{
+		{worktree.Status{}, "-"},
+		{worktree.Status{Upstream: "origin/main"}, "up to date"},
+		{worktree.Status{Upstream: "origin/main", Ahead: 2}, "↑2"},
+		{worktree.Status{Upstream: "origin/main", Behind: 1}, "↓1"},
+		{worktree.Status{Upstream: "origin/main", Ahead: 2, Behind: 1}, "↑2 ↓1"},
+	}
+	for _, tt := range tests {
+		if got := formatSync(tt.st); got != tt.want {
+			t.Errorf("formatSync(%+v) = %q, want %q", tt.st, got, tt.want)
+		}
+	}
+}
+
+func TestPrintWorktreeTable(t *testing.T) {
+	now := time.Now()
+	statuses := []worktree.Status{
+		{Repo: "github.com/o/r", Branch: "main", IsMain: true, Commit: "1234567890", Path: "/src/r", Upstream: "origin/main", LastCommit: now.Add(-2 * time.Hour)},
+		{Repo: "github.com/o/r", Branch: "feat", Commit: "abcdef1234", Path: "/wt/r__feat", Dirty: true},
+	}
+
+	var buf bytes.Buffer
+	printWorktreeTable(&buf, statuses, true, now)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "REPO") {
+		t.Errorf("header = %q, want REPO column", lines[0])
+	}
+	for _, want := range []string{"main (main)", "1234567", "clean", "up to date", "2h", "/src/r"} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("row %q missing %q", lines[1], want)
+		}
+	}
+	for _, want := range []string{"feat", "abcdef1", "dirty", "/wt/r__feat"} {
+		if !strings.Contains(lines[2], want) {
+			t.Errorf("row %q missing %q", lines[2], want)
+		}
+	}
+}
@@ -9,30 +9,55 @@ import (
 )
 
 var wktNewCmd = &cobra.Command{
-	Use:   "new <branch>",
-	Short: "Create a new worktree with a new branch",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runWktNew,
+	Use:   "new [branch]",
+	Short: "Create a new worktree for a new or existing branch",
+	Long: `Create a worktree for a branch and cd into it.
+
+An existing local branch is checked out directly. A branch that only exists on
+a remote (e.g. origin/feature-x) is created as a local tracking branch. Any
+other name becomes a new branch starting at --base (default: current HEAD of
+the main worktree).
+
+Use --from to check out a specific remote branch; the branch name then
+defaults to the ref without its remote prefix.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranches,
+	RunE:              runWktNew,
 }
 
 func init() {
+	wktNewCmd.Flags().String("from", "", "existing local or remote branch to check out (e.g. origin/feature-x)")
+	wktNewCmd.Flags().String("base", "", "start point for a new branch (branch, tag, or commit)")
+	wktNewCmd.MarkFlagsMutuallyExclusive("from", "base")
+	_ = wktNewCmd.RegisterFlagCompletionFunc("from", completeRefs)
+	_ = wktNewCmd.RegisterFlagCompletionFunc("base", completeRefs)
 	wktCmd.AddCommand(wktNewCmd)
 }
 
 func runWktNew(cmd *cobra.Command, args []string) error {
-	branchName := args[0]
+	var branchName string
+	if len(args) == 1 {
+		branchName = args[0]
+	}
+	from, _ := cmd.Flags().GetString("from")
+	base, _ := cmd.Flags().GetString("base")
+	if branchName == "" && from == "" {
+		return fmt.Errorf("specify a branch name or --from <ref>")
+	}
 
 	repoInfo, err := worktree.DetectCurrentRepo()
 	if err != nil {
 		return err
 	}
 
-	path, err := worktree.CreateWorktree(repoInfo, branchName)
+	path, err := worktree.CreateWorktree(repoInfo, branchName, worktree.CreateOptions{From: from, Base: base})
 	if err != nil {
 		return err
 	}
 
+	if branchName == "" {
+		branchName = from
+	}
 	fmt.Fprintf(os.Stderr, "created worktree for branch %q at %s\n", branchName, path)
-	fmt.Printf("cd %s\n", path)
-	return nil
+	return emitCD(os.Stdout, path)
 }
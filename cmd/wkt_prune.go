@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dsaiztc/dev/internal/fuzzy"
+	"github.com/dsaiztc/dev/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var wktPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove merged and stale worktrees",
+	Long: `Finds linked worktrees whose branch is merged into the default branch,
+whose upstream branch is gone, or whose directory no longer exists, and lets you
+pick the ones to remove in a fuzzy finder (Tab toggles, Ctrl-A selects all
+matching, Enter confirms).
+
+Worktrees are removed like dev wkt rm: those with uncommitted changes, untracked
+files, stashes or unpushed commits start unselected and are refused unless
+--force is given. The worktree you are currently in is never pruned.
+
+With --all, looks at every repo that has linked worktrees under the worktree root.`,
+	Args: cobra.NoArgs,
+	RunE: runWktPrune,
+}
+
+func init() {
+	wktPruneCmd.Flags().Bool("all", false, "prune worktrees of every repo under the worktree root")
+	wktPruneCmd.Flags().Bool("dry-run", false, "list the stale worktrees without removing anything")
+	wktPruneCmd.Flags().Bool("force", false, "remove even if uncommitted or unpushed work would be lost")
+	wktCmd.AddCommand(wktPruneCmd)
+}
+
+func runWktPrune(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	repoInfos, err := worktreeRepos(all)
+	if err != nil {
+		return err
+	}
+
+	cwd, _ := os.Getwd()
+	var candidates []worktree.PruneCandidate
+	for _, repoInfo := range repoInfos {
+		repoCandidates, err := worktree.FindPruneCandidates(repoInfo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %s: %v\n", repoInfo.MainPath, err)
+			continue
+		}
+		for _, c := range repoCandidates {
+			if isWithin(cwd, c.Worktree.Path) {
+				fmt.Fprintf(os.Stderr, "skipping %q: it is the current worktree\n", c.Worktree.Branch)
+				continue
+			}
+			candidates = append(candidates, c)
+		}
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(os.Stderr, "nothing to prune")
+		return nil
+	}
+
+	if dryRun {
+		printPruneTable(os.Stdout, candidates)
+		return nil
+	}
+
+	labels := make([]string, len(candidates))
+	preselected := make([]bool, len(candidates))
+	byLabel := make(map[string]worktree.PruneCandidate)
+	for i, c := range candidates {
+		labels[i] = pruneLabel(c, all)
+		preselected[i] = force || c.Risks.Empty()
+		byLabel[labels[i]] = c
+	}
+
+	selected, err := fuzzy.RunMulti(labels, preselected)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(os.Stderr, "cancelled")
+		return nil
+	}
+
+	var failed int
+	for _, label := range selected {
+		c := byLabel[label]
+		if _, err := worktree.RemoveWorktree(c.Repo, c.Worktree, worktree.RemoveOptions{Force: force}); err != nil {
+			fmt.Fprintf(os.Stderr, "could not remove %q: %v\n", c.Worktree.Branch, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "removed worktree %q\n", c.Worktree.Branch)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d worktree(s) could not be removed", failed, len(selected))
+	}
+	return nil
+}
+
+// pruneLabel describes a candidate on one line for the finder.
+func pruneLabel(c worktree.PruneCandidate, withRepo bool) string {
+	name := c.Worktree.Branch
+	if name == "" {
+		name = filepath.Base(c.Worktree.Path)
+	}
+	if withRepo {
+		name = c.Repo.Name() + " " + name
+	}
+	label := fmt.Sprintf("%s (%s)", name, strings.Join(c.Reasons, ", "))
+	if !c.Risks.Empty() {
+		label += " ! " + strings.Join(c.Risks.Summary(), ", ")
+	}
+	return label
+}
+
+// printPruneTable lists candidates for --dry-run.
+func printPruneTable(w io.Writer, candidates []worktree.PruneCandidate) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPO\tBRANCH\tREASON\tUNSAVED WORK\tPATH")
+	for _, c := range candidates {
+		unsaved := "-"
+		if !c.Risks.Empty() {
+			unsaved = strings.Join(c.Risks.Summary(), ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", c.Repo.Name(), c.Worktree.Branch, strings.Join(c.Reasons, ", "), unsaved, c.Worktree.Path)
+	}
+	tw.Flush()
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
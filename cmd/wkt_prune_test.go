@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/worktree"
+)
+
+func TestPruneLabel(t *testing.T) {
+	repo := &worktree.RepoInfo{Source: "github.com", Org: "o", Repo: "r"}
+	c := worktree.PruneCandidate{
+		Repo:     repo,
+		Worktree: worktree.Worktree{Branch: "feat", Path: "/wt/r__feat"},
+		Reasons:  []string{worktree.ReasonMerged, worktree.ReasonUpstreamGone},
+	}
+
+	if got, want := pruneLabel(c, false), "feat (merged, upstream gone)"; got != want {
+		t.Errorf("pruneLabel() = %q, want %q", got, want)
+	}
+	if got, want := pruneLabel(c, true), "github.com/o/r feat (merged, upstream gone)"; got != want {
+		t.Errorf("pruneLabel(withRepo) = %q, want %q", got, want)
+	}
+
+	c.Risks = worktree.Risks{Unpushed: 2}
+	if got, want := pruneLabel(c, false), "feat (merged, upstream gone) ! 2 commit(s) not on any remote"; got != want {
+		t.Errorf("pruneLabel(risky) = %q, want %q", got, want)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		path, dir string
+		want      bool
+	}{
+		{"/wt/r__feat", "/wt/r__feat", true},
+		{"/wt/r__feat/sub", "/wt/r__feat", true},
+		{"/wt/r__feature", "/wt/r__feat", false},
+		{"/src/r", "/wt/r__feat", false},
+	}
+	for _, tt := range tests {
+		if got := isWithin(tt.path, tt.dir); got != tt.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", tt.path, tt.dir, got, tt.want)
+		}
+	}
+}
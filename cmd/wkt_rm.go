@@ -14,22 +14,32 @@ import (
 var wktRmCmd = &cobra.Command{
 	Use:   "rm [branch]",
 	Short: "Remove a worktree and its branch",
-	Long: `Remove a worktree, its local branch, and its remote branch.
+	Long: `Remove a worktree and its local branch.
 
 From a linked worktree (no args): removes the current worktree.
-From the main worktree: specify a branch name or pick one from the fuzzy finder.
-
-This command deletes the worktree directory, the local branch (git branch -D),
-and the remote branch (git push origin --delete). Always prompts for confirmation.`,
-	Args:  cobra.MaximumNArgs(1),
-	RunE:  runWktRm,
+From the main worktree: specify a branch name or pick worktrees from the fuzzy
+finder (Tab selects several, Ctrl-A all that match).
+
+This command deletes the worktree directory and the local branch, and with
+--delete-remote also the remote branch (git push origin --delete). It refuses
+to remove a worktree with uncommitted changes, untracked files, stashes made on
+its branch, or commits not on any remote unless --force is given. Always
+prompts for confirmation. A failing pre-wkt-rm hook aborts the removal.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeWorktreeBranches(false),
+	RunE:              runWktRm,
 }
 
 func init() {
+	wktRmCmd.Flags().Bool("force", false, "remove even if uncommitted or unpushed work would be lost")
+	wktRmCmd.Flags().Bool("delete-remote", false, "also delete the branch on origin")
 	wktCmd.AddCommand(wktRmCmd)
 }
 
 func runWktRm(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	deleteRemote, _ := cmd.Flags().GetBool("delete-remote")
+
 	repoInfo, err := worktree.DetectCurrentRepo()
 	if err != nil {
 		return err
@@ -40,61 +50,28 @@ func runWktRm(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var target worktree.Worktree
-	var found bool
+	var targets []worktree.Worktree
 
 	if repoInfo.IsLinked {
 		// From linked worktree: remove the current one
 		for _, wt := range worktrees {
 			if wt.Path == repoInfo.CurrentPath {
-				target = wt
-				found = true
+				targets = append(targets, wt)
 				break
 			}
 		}
-		if !found {
+		if len(targets) == 0 {
 			return fmt.Errorf("could not find current worktree in list")
 		}
 	} else if len(args) == 1 {
 		// From main worktree with arg: fuzzy match against branch names
-		query := args[0]
-		for _, wt := range worktrees {
-			if wt.IsMain {
-				continue
-			}
-			if wt.Branch == query {
-				target = wt
-				found = true
-				break
-			}
-		}
-		if !found {
-			// Try substring/fuzzy match
-			var candidates []string
-			branchMap := make(map[string]worktree.Worktree)
-			for _, wt := range worktrees {
-				if wt.IsMain {
-					continue
-				}
-				candidates = append(candidates, wt.Branch)
-				branchMap[wt.Branch] = wt
-			}
-			if len(candidates) == 0 {
-				return fmt.Errorf("no linked worktrees to remove")
-			}
-			for _, c := range candidates {
-				if strings.Contains(c, query) {
-					target = branchMap[c]
-					found = true
-					break
-				}
-			}
-			if !found {
-				return fmt.Errorf("no worktree matching %q", query)
-			}
+		target, err := matchWorktree(worktrees, args[0])
+		if err != nil {
+			return err
 		}
+		targets = append(targets, target)
 	} else {
-		// From main worktree without args: fuzzy finder
+		// From main worktree without args: fuzzy finder, Tab picks several
 		var items []string
 		pathMap := make(map[string]worktree.Worktree)
 		for _, wt := range worktrees {
@@ -108,35 +85,114 @@ func runWktRm(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("no linked worktrees to remove")
 		}
 
-		selected, err := fuzzy.Run(items)
+		selected, err := fuzzy.RunMulti(items, nil)
 		if err != nil {
 			return err
 		}
-		if selected == "" {
+		if len(selected) == 0 {
 			return nil
 		}
-		target = pathMap[selected]
+		for _, branch := range selected {
+			targets = append(targets, pathMap[branch])
+		}
+	}
+
+	var risky []string
+	for _, target := range targets {
+		risks, err := worktree.CheckRemoval(repoInfo, target)
+		if err != nil {
+			return err
+		}
+		if !risks.Empty() {
+			fmt.Fprintf(os.Stderr, "worktree %q has work that would be lost:\n", target.Branch)
+			for _, line := range risks.Summary() {
+				fmt.Fprintf(os.Stderr, "  - %s\n", line)
+			}
+			risky = append(risky, target.Branch)
+		}
+	}
+	if len(risky) > 0 && !force {
+		if len(targets) == 1 {
+			return fmt.Errorf("refusing to remove %q; use --force to remove it anyway", risky[0])
+		}
+		return fmt.Errorf("refusing to remove %d of %d worktrees (%s); use --force to remove them anyway", len(risky), len(targets), strings.Join(risky, ", "))
 	}
 
 	// Confirm removal
-	fmt.Fprintf(os.Stderr, "remove worktree %q (branch: %s, path: %s)? [y/N] ", target.Branch, target.Branch, target.Path)
+	if len(targets) == 1 {
+		what := "worktree and local branch"
+		if deleteRemote {
+			what = "worktree, local branch and remote branch"
+		}
+		fmt.Fprintf(os.Stderr, "remove %s %q (path: %s)? [y/N] ", what, targets[0].Branch, targets[0].Path)
+	} else {
+		what := "worktrees and local branches"
+		if deleteRemote {
+			what = "worktrees, local branches and remote branches"
+		}
+		for _, target := range targets {
+			fmt.Fprintf(os.Stderr, "  %s (path: %s)\n", target.Branch, target.Path)
+		}
+		fmt.Fprintf(os.Stderr, "remove these %d %s? [y/N] ", len(targets), what)
+	}
 	if !confirmFromTTY() {
 		fmt.Fprintln(os.Stderr, "cancelled")
 		return nil
 	}
 
-	cdPath, err := worktree.RemoveWorktree(repoInfo, target)
-	if err != nil {
-		return err
+	var cdPath string
+	var failed int
+	for _, target := range targets {
+		path, err := worktree.RemoveWorktree(repoInfo, target, worktree.RemoveOptions{Force: force, DeleteRemote: deleteRemote})
+		if err != nil {
+			if len(targets) == 1 {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "could not remove %q: %v\n", target.Branch, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "removed worktree %q\n", target.Branch)
+		if path != "" {
+			cdPath = path
+		}
 	}
 
-	fmt.Fprintf(os.Stderr, "removed worktree %q\n", target.Branch)
 	if cdPath != "" {
-		fmt.Printf("cd %s\n", cdPath)
+		if err := emitCD(os.Stdout, cdPath); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d worktree(s) could not be removed", failed, len(targets))
 	}
 	return nil
 }
 
+// matchWorktree returns the linked worktree whose branch is query or, failing
+// that, the first one whose branch contains it.
+func matchWorktree(worktrees []worktree.Worktree, query string) (worktree.Worktree, error) {
+	var candidates []worktree.Worktree
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			continue
+		}
+		if wt.Branch == query {
+			return wt, nil
+		}
+		candidates = append(candidates, wt)
+	}
+	if len(candidates) == 0 {
+		return worktree.Worktree{}, fmt.Errorf("no linked worktrees to remove")
+	}
+	for _, wt := range candidates {
+		if strings.Contains(wt.Branch, query) {
+			return wt, nil
+		}
+	}
+	return worktree.Worktree{}, fmt.Errorf("no worktree matching %q", query)
+}
+
 func confirmFromTTY() bool {
 	tty, err := os.Open("/dev/tty")
 	if err != nil {
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/worktree"
+)
+
+func TestMatchWorktree(t *testing.T) {
+	worktrees := []worktree.Worktree{
+		{Branch: "main", IsMain: true},
+		{Branch: "feature-login-v2"},
+		{Branch: "feature-login"},
+	}
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"feature-login", "feature-login", false}, // exact match wins over an earlier substring match
+		{"login", "feature-login-v2", false},
+		{"main", "", true}, // the main worktree is never a target
+		{"nope", "", true},
+	}
+	for _, tt := range tests {
+		got, err := matchWorktree(worktrees, tt.query)
+		if (err != nil) != tt.wantErr || got.Branch != tt.want {
+			t.Errorf("matchWorktree(%q) = %q, %v; want %q (error: %v)", tt.query, got.Branch, err, tt.want, tt.wantErr)
+		}
+	}
+	if _, err := matchWorktree(worktrees[:1], "x"); err == nil || err.Error() != "no linked worktrees to remove" {
+		t.Errorf("with only the main worktree, err = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.json")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent writers must leave one complete file and no temp files behind
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Write(path, []byte(fmt.Sprintf("writer %d", i)), 0o644); err != nil {
+				t.Errorf("Write: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if _, err := fmt.Sscanf(string(data), "writer %d", &n); err != nil {
+		t.Errorf("content = %q, want one writer's data", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only the file", len(entries))
+	}
+}
+
+func TestWrite_MissingDir(t *testing.T) {
+	if err := Write(filepath.Join(t.TempDir(), "missing", "f"), nil, 0o644); err == nil {
+		t.Error("Write into a missing directory should fail")
+	}
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/dsaiztc/dev/internal/repourl"
 )
 
 // Config holds user defaults for the dev CLI.
@@ -13,21 +16,193 @@ type Config struct {
 	DefaultSource string `json:"default_source"`
 	DefaultOrg    string `json:"default_org"`
 	WorktreeRoot  string `json:"worktree_root,omitempty"`
+	SrcRoot       string `json:"src_root,omitempty"`
+
+	// WorktreeFiles and Hooks apply to every repo; Repos adds per-repo settings.
+	WorktreeFiles WorktreeFiles         `json:"worktree_files,omitzero"`
+	Hooks         Hooks                 `json:"hooks,omitempty"`
+	Repos         map[string]RepoConfig `json:"repos,omitempty"` // keyed by source/org/repo
+
+	// RepoHooks run on dev clone and dev new for repos matching a pattern.
+	RepoHooks []RepoHook `json:"repo_hooks,omitempty"`
+
+	// Identities are git identity profiles; the first whose pattern matches a repo applies.
+	Identities []Identity `json:"identities,omitempty"`
+
+	// TemplatesDir holds local dev new templates, one directory per template;
+	// Templates maps further template names to a git URL or local path.
+	TemplatesDir string            `json:"templates_dir,omitempty"`
+	Templates    map[string]string `json:"templates,omitempty"`
+
+	// Forges configures the hosting APIs used by dev new --remote and dev clone --org, keyed by source.
+	Forges map[string]ForgeConfig `json:"forges,omitempty"`
+
+	// Groups name sets of source/org/repo globs for selecting repos (e.g. dev exec --group).
+	Groups map[string][]string `json:"groups,omitempty"`
+}
+
+// ForgeConfig describes the API of a git hosting service. github.com and
+// gitlab.com work without configuration as long as a token is available.
+type ForgeConfig struct {
+	Type     string `json:"type,omitempty"`     // "github", "gitlab" or "gitea"
+	APIURL   string `json:"api_url,omitempty"`  // defaults to the type's API path on the source host
+	Token    string `json:"token,omitempty"`    // used when the type's token env var is unset
+	Protocol string `json:"protocol,omitempty"` // "ssh" (default) or "https" remotes
+}
+
+// Identity is a git identity profile applied to the local git config of
+// repos whose source/org/repo path matches one of Match (repourl.Match patterns).
+type Identity struct {
+	Profile    string   `json:"profile"`               // label used in reports, e.g. "work"
+	Name       string   `json:"name"`                  // user.name
+	Email      string   `json:"email"`                 // user.email
+	SigningKey string   `json:"signing_key,omitempty"` // user.signingkey
+	Match      []string `json:"match"`
+}
+
+// IdentityFor returns the first identity profile matching repo (a
+// source/org/repo path), or nil if none does.
+func (c *Config) IdentityFor(repo string) *Identity {
+	for i, id := range c.Identities {
+		for _, pattern := range id.Match {
+			if repourl.Match(pattern, repo) {
+				return &c.Identities[i]
+			}
+		}
+	}
+	return nil
+}
+
+// RepoHook runs hooks (e.g. "post-clone", "post-new") on every repo whose
+// source/org/repo path matches Match, a repourl.Match pattern such as
+// "github.com/mycompany/*".
+type RepoHook struct {
+	Match string `json:"match"`
+	Hooks Hooks  `json:"hooks"`
+}
+
+// RepoConfig holds settings for a single repo.
+type RepoConfig struct {
+	WorktreeFiles WorktreeFiles `json:"worktree_files,omitzero"`
+	Hooks         Hooks         `json:"hooks,omitempty"`
+
+	// TrustCommittedHooks lets the repo's .dev/hooks scripts replace the
+	// configured worktree hooks. Off by default, since any checked-out branch
+	// could change them.
+	TrustCommittedHooks bool `json:"trust_committed_hooks,omitempty"`
+}
+
+// Hooks maps a hook name (e.g. "post-wkt-new") to the shell commands it runs.
+type Hooks map[string][]string
+
+// HookCommands returns the commands configured for the named hook of repo
+// (a source/org/repo path). Commands set for the repo replace the global ones.
+func (c *Config) HookCommands(repo, name string) []string {
+	if cmds, ok := c.Repos[repo].Hooks[name]; ok {
+		return cmds
+	}
+	return c.Hooks[name]
+}
+
+// TrustsCommittedHooks reports whether the .dev/hooks scripts of repo (a
+// source/org/repo path) may run.
+func (c *Config) TrustsCommittedHooks(repo string) bool {
+	return c.Repos[repo].TrustCommittedHooks
+}
+
+// WorktreeFiles lists glob patterns, relative to the repo root, of untracked
+// files and directories (e.g. ".env", "node_modules") that are carried over
+// from the main worktree into every new worktree.
+type WorktreeFiles struct {
+	Copy    []string `json:"copy,omitempty"`    // copied, using reflinks where supported
+	Symlink []string `json:"symlink,omitempty"` // symlinked to the main worktree's copy
+}
+
+// WorktreeFilesFor returns the global worktree file patterns followed by
+// those configured for repo (a source/org/repo path).
+func (c *Config) WorktreeFilesFor(repo string) WorktreeFiles {
+	files := WorktreeFiles{
+		Copy:    append([]string{}, c.WorktreeFiles.Copy...),
+		Symlink: append([]string{}, c.WorktreeFiles.Symlink...),
+	}
+	if rc, ok := c.Repos[repo]; ok {
+		files.Copy = append(files.Copy, rc.WorktreeFiles.Copy...)
+		files.Symlink = append(files.Symlink, rc.WorktreeFiles.Symlink...)
+	}
+	return files
+}
+
+// RepoHookCommands returns the commands of every repo_hooks entry matching
+// repo (a source/org/repo path) for the named hook, in config order.
+func (c *Config) RepoHookCommands(repo, name string) []string {
+	var cmds []string
+	for _, h := range c.RepoHooks {
+		if repourl.Match(h.Match, repo) {
+			cmds = append(cmds, h.Hooks[name]...)
+		}
+	}
+	return cmds
+}
+
+// SrcRootEnv is the environment variable that overrides the configured source root.
+const SrcRootEnv = "DEV_SRC_ROOT"
+
+// GetSrcRoot returns the source root that repositories are organized under.
+// DEV_SRC_ROOT takes precedence over the configured src_root, which takes
+// precedence over the default ~/src. Expands a leading ~ to the user's home directory.
+func (c *Config) GetSrcRoot() string {
+	if env := os.Getenv(SrcRootEnv); env != "" {
+		return ExpandHome(env)
+	}
+	if c.SrcRoot != "" {
+		return ExpandHome(c.SrcRoot)
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "src")
 }
 
 // GetWorktreeRoot returns the configured worktree root or the default ~/src__worktrees.
 // Expands a leading ~ to the user's home directory.
 func (c *Config) GetWorktreeRoot() string {
-	homeDir, _ := os.UserHomeDir()
 	if c.WorktreeRoot != "" {
-		if strings.HasPrefix(c.WorktreeRoot, "~/") {
-			return filepath.Join(homeDir, c.WorktreeRoot[2:])
-		}
-		return c.WorktreeRoot
+		return ExpandHome(c.WorktreeRoot)
 	}
+	homeDir, _ := os.UserHomeDir()
 	return filepath.Join(homeDir, "src__worktrees")
 }
 
+// GetTemplatesDir returns the configured templates directory or the default
+// ~/.config/dev/templates. Expands a leading ~ to the user's home directory.
+func (c *Config) GetTemplatesDir() string {
+	if c.TemplatesDir != "" {
+		return ExpandHome(c.TemplatesDir)
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "dev", "templates")
+}
+
+// ExpandHome expands a leading ~ in path to the user's home directory.
+func ExpandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, _ := os.UserHomeDir()
+		return filepath.Join(homeDir, path[1:])
+	}
+	return path
+}
+
+// SrcRoot resolves the source root from the config file, falling back to the
+// defaults when no config exists. Every command resolves ~/src through here.
+func SrcRoot() (string, error) {
+	cfg, err := Load()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("could not load config: %w", err)
+		}
+		cfg = &Config{}
+	}
+	return cfg.GetSrcRoot(), nil
+}
+
 // Path returns the config file path (~/.config/dev/config.json).
 func Path() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -132,3 +132,160 @@ func TestSaveCreatesParentDirs(t *testing.T) {
 		t.Errorf("unexpected config: %+v", got)
 	}
 }
+
+func TestGetSrcRoot_Default(t *testing.T) {
+	t.Setenv(SrcRootEnv, "")
+	cfg := &Config{}
+	got := cfg.GetSrcRoot()
+	homeDir, _ := os.UserHomeDir()
+	want := filepath.Join(homeDir, "src")
+	if got != want {
+		t.Errorf("GetSrcRoot() = %q, want %q", got, want)
+	}
+}
+
+func TestGetSrcRoot_Custom(t *testing.T) {
+	t.Setenv(SrcRootEnv, "")
+	cfg := &Config{SrcRoot: "/workspace"}
+	if got := cfg.GetSrcRoot(); got != "/workspace" {
+		t.Errorf("GetSrcRoot() = %q, want %q", got, "/workspace")
+	}
+}
+
+func TestGetSrcRoot_TildeExpansion(t *testing.T) {
+	t.Setenv(SrcRootEnv, "")
+	cfg := &Config{SrcRoot: "~/code"}
+	got := cfg.GetSrcRoot()
+	homeDir, _ := os.UserHomeDir()
+	want := filepath.Join(homeDir, "code")
+	if got != want {
+		t.Errorf("GetSrcRoot() = %q, want %q", got, want)
+	}
+}
+
+func TestGetSrcRoot_EnvOverride(t *testing.T) {
+	t.Setenv(SrcRootEnv, "/ci/workspace")
+	cfg := &Config{SrcRoot: "~/code"}
+	if got := cfg.GetSrcRoot(); got != "/ci/workspace" {
+		t.Errorf("GetSrcRoot() = %q, want %q", got, "/ci/workspace")
+	}
+}
+
+func TestSrcRoot_NoConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(SrcRootEnv, "")
+
+	got, err := SrcRoot()
+	if err != nil {
+		t.Fatalf("SrcRoot: %v", err)
+	}
+	if want := filepath.Join(home, "src"); got != want {
+		t.Errorf("SrcRoot() = %q, want %q", got, want)
+	}
+}
+
+func TestSrcRoot_FromConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(SrcRootEnv, "")
+
+	if err := Save(&Config{SrcRoot: "~/code"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := SrcRoot()
+	if err != nil {
+		t.Fatalf("SrcRoot: %v", err)
+	}
+	if want := filepath.Join(home, "code"); got != want {
+		t.Errorf("SrcRoot() = %q, want %q", got, want)
+	}
+}
+
+func TestWorktreeFilesFor(t *testing.T) {
+	cfg := &Config{
+		WorktreeFiles: WorktreeFiles{Copy: []string{".env"}, Symlink: []string{".idea"}},
+		Repos: map[string]RepoConfig{
+			"github.com/o/web": {WorktreeFiles: WorktreeFiles{Symlink: []string{"node_modules"}}},
+		},
+	}
+
+	got := cfg.WorktreeFilesFor("github.com/o/web")
+	if strings.Join(got.Copy, ",") != ".env" || strings.Join(got.Symlink, ",") != ".idea,node_modules" {
+		t.Errorf("WorktreeFilesFor(web) = %+v, want global plus repo patterns", got)
+	}
+
+	got = cfg.WorktreeFilesFor("github.com/o/other")
+	if strings.Join(got.Copy, ",") != ".env" || strings.Join(got.Symlink, ",") != ".idea" {
+		t.Errorf("WorktreeFilesFor(other) = %+v, want global patterns only", got)
+	}
+	if len(cfg.WorktreeFiles.Symlink) != 1 {
+		t.Error("WorktreeFilesFor modified the global patterns")
+	}
+}
+
+func TestWorktreeFilesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := SaveTo(&Config{DefaultSource: "github.com"}, path); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "worktree_files") || strings.Contains(string(data), "repos") {
+		t.Errorf("empty worktree settings should be omitted, got:\n%s", data)
+	}
+}
+
+func TestRepoHookCommands(t *testing.T) {
+	cfg := &Config{
+		RepoHooks: []RepoHook{
+			{Match: "github.com/**", Hooks: Hooks{"post-clone": {"pre-commit install"}}},
+			{Match: "github.com/mycompany/*", Hooks: Hooks{"post-clone": {"make setup"}, "post-new": {"cp ~/LICENSE ."}}},
+		},
+	}
+
+	tests := []struct {
+		repo, hook string
+		want       string
+	}{
+		{"github.com/mycompany/api", "post-clone", "pre-commit install,make setup"},
+		{"github.com/me/dotfiles", "post-clone", "pre-commit install"},
+		{"github.com/mycompany/api", "post-new", "cp ~/LICENSE ."},
+		{"gitlab.com/team/service", "post-clone", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(cfg.RepoHookCommands(tt.repo, tt.hook), ","); got != tt.want {
+			t.Errorf("RepoHookCommands(%q, %q) = %q, want %q", tt.repo, tt.hook, got, tt.want)
+		}
+	}
+}
+
+func TestIdentityFor(t *testing.T) {
+	cfg := &Config{
+		Identities: []Identity{
+			{Profile: "work", Email: "me@mycompany.com", Match: []string{"github.com/mycompany/*", "gitlab.mycompany.com/**"}},
+			{Profile: "personal", Email: "me@example.com", Match: []string{"github.com/*/*"}},
+		},
+	}
+
+	tests := []struct {
+		repo, want string
+	}{
+		{"github.com/mycompany/api", "work"},
+		{"gitlab.mycompany.com/team/sub/svc", "work"},
+		{"github.com/dsaiztc/dev", "personal"},
+		{"gitlab.com/team/service", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if id := cfg.IdentityFor(tt.repo); id != nil {
+			got = id.Profile
+		}
+		if got != tt.want {
+			t.Errorf("IdentityFor(%q) = %q, want %q", tt.repo, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+// Forge types.
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+	Gitea  = "gitea"
+)
+
+// tokenEnv lists the environment variables checked for each type's token, in order.
+var tokenEnv = map[string][]string{
+	GitHub: {"GITHUB_TOKEN", "GH_TOKEN"},
+	GitLab: {"GITLAB_TOKEN"},
+	Gitea:  {"GITEA_TOKEN"},
+}
+
+// Repo is a repository as reported by a forge.
+type Repo struct {
+	Path     string // org/name; org may contain subgroups on GitLab
+	SSHURL   string
+	HTTPSURL string
+	WebURL   string
+	Archived bool
+	Fork     bool
+	Topics   []string
+}
+
+// CloneURL returns the repo's https URL for protocol "https" and its SSH URL otherwise.
+func (r Repo) CloneURL(protocol string) string {
+	if protocol == "https" {
+		return r.HTTPSURL
+	}
+	return r.SSHURL
+}
+
+// Client talks to a forge's REST API.
+type Client interface {
+	// CreateRepo creates an empty repo named name under org, which may be
+	// the authenticated user's own namespace.
+	CreateRepo(ctx context.Context, org, name string, private bool) (*Repo, error)
+	// ListRepos lists the repos of org, which may be a user. On GitLab the
+	// repos of its subgroups are included.
+	ListRepos(ctx context.Context, org string) ([]Repo, error)
+}
+
+// Settings are the resolved API settings for a source.
+type Settings struct {
+	Type     string
+	APIURL   string
+	Token    string
+	Protocol string
+}
+
+// Resolve determines the forge settings for source from cfg, applying
+// defaults for github.com and gitlab.com. The token environment variable of
+// the forge type (e.g. GITHUB_TOKEN) takes precedence over a configured token.
+func Resolve(source string, cfg *config.Config) (Settings, error) {
+	fc := cfg.Forges[source]
+	s := Settings{Type: fc.Type, APIURL: fc.APIURL, Token: fc.Token, Protocol: fc.Protocol}
+
+	if s.Type == "" {
+		switch source {
+		case "github.com":
+			s.Type = GitHub
+		case "gitlab.com":
+			s.Type = GitLab
+		default:
+			return s, fmt.Errorf("unknown forge for %s; set its type under forges in the config file", source)
+		}
+	}
+
+	if s.APIURL == "" {
+		switch s.Type {
+		case GitHub:
+			s.APIURL = "https://" + source + "/api/v3"
+			if source == "github.com" {
+				s.APIURL = "https://api.github.com"
+			}
+		case GitLab:
+			s.APIURL = "https://" + source + "/api/v4"
+		case Gitea:
+			s.APIURL = "https://" + source + "/api/v1"
+		}
+	}
+	s.APIURL = strings.TrimSuffix(s.APIURL, "/")
+
+	envs, ok := tokenEnv[s.Type]
+	if !ok {
+		return s, fmt.Errorf("unsupported forge type %q for %s (supported: github, gitlab, gitea)", s.Type, source)
+	}
+	for _, env := range envs {
+		if token := os.Getenv(env); token != "" {
+			s.Token = token
+			break
+		}
+	}
+	if s.Token == "" {
+		return s, fmt.Errorf("no API token for %s; set %s or a token under forges in the config file", source, envs[0])
+	}
+	return s, nil
+}
+
+// New returns a client for the resolved settings.
+func New(s Settings) Client {
+	a := &api{baseURL: s.APIURL, http: &http.Client{Timeout: 30 * time.Second}}
+	switch s.Type {
+	case GitLab:
+		a.header, a.value = "PRIVATE-TOKEN", s.Token
+		return &gitlabClient{a}
+	case Gitea:
+		a.header, a.value = "Authorization", "token "+s.Token
+		return &giteaClient{a}
+	}
+	a.header, a.value = "Authorization", "Bearer "+s.Token
+	return &githubClient{a}
+}
+
+// api performs authenticated JSON requests against a REST API.
+type api struct {
+	baseURL string
+	http    *http.Client
+	header  string // authentication header name
+	value   string // authentication header value
+}
+
+// do sends body (if non-nil) as JSON to path and decodes the response into out
+// (if non-nil). Non-2xx responses become errors carrying the API's message.
+func (a *api) do(ctx context.Context, method, path string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(a.header, a.value)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{method: method, path: path, status: resp.Status, code: resp.StatusCode, message: apiMessage(data)}
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%s %s: could not parse response: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// apiError is a non-2xx API response.
+type apiError struct {
+	method, path, status string
+	code                 int
+	message              string // as returned by apiMessage
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %s%s", e.method, e.path, e.status, e.message)
+}
+
+// isNotFound reports whether err is a 404 API response.
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.code == http.StatusNotFound
+}
+
+// listAll GETs every page of the list at path, requesting limit items per
+// page with the limitParam query parameter, until a short page is returned.
+func listAll[T any](ctx context.Context, a *api, path, limitParam string, limit int) ([]T, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	var all []T
+	for page := 1; ; page++ {
+		var batch []T
+		pagePath := fmt.Sprintf("%s%s%s=%d&page=%d", path, sep, limitParam, limit, page)
+		if err := a.do(ctx, http.MethodGet, pagePath, nil, &batch); err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < limit {
+			return all, nil
+		}
+	}
+}
+
+// apiMessage extracts the error message from a GitHub, GitLab or Gitea
+// error body, formatted as ": <message>", or "" if there is none.
+func apiMessage(data []byte) string {
+	var body struct {
+		Message any `json:"message"`
+		Error   any `json:"error"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return ""
+	}
+	for _, m := range []any{body.Message, body.Error} {
+		if m != nil && m != "" {
+			return fmt.Sprintf(": %v", m)
+		}
+	}
+	return ""
+}
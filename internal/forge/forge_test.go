@@ -0,0 +1,325 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+// request is what the stub server saw of a request.
+type request struct {
+	Method, Path, Auth string
+	Body               map[string]any
+}
+
+// stubServer serves canned JSON responses keyed by "METHOD /path", or by
+// "METHOD /path?query" when the request has a query, and records every
+// request it gets.
+func stubServer(t *testing.T, responses map[string]string) (*httptest.Server, *[]request) {
+	t.Helper()
+	var seen []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := request{Method: r.Method, Path: r.URL.EscapedPath()}
+		if r.URL.RawQuery != "" {
+			req.Path += "?" + r.URL.RawQuery
+		}
+		req.Auth = r.Header.Get("Authorization") + r.Header.Get("PRIVATE-TOKEN")
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			if err := json.Unmarshal(data, &req.Body); err != nil {
+				t.Errorf("request body is not JSON: %s", data)
+			}
+		}
+		seen = append(seen, req)
+
+		resp, ok := responses[r.Method+" "+req.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"Not Found"}`)
+			return
+		}
+		io.WriteString(w, resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &seen
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("GITEA_TOKEN", "")
+
+	cfg := &config.Config{Forges: map[string]config.ForgeConfig{
+		"gitlab.com":         {Token: "cfg-token"},
+		"git.mycompany.com":  {Type: Gitea, Token: "gitea-token", Protocol: "https"},
+		"ghe.mycompany.com":  {Type: GitHub, APIURL: "https://ghe.mycompany.com/custom/", Token: "t"},
+		"code.mycompany.com": {Type: "bitbucket", Token: "t"},
+	}}
+
+	tests := []struct {
+		source  string
+		want    Settings
+		wantErr string
+	}{
+		{source: "gitlab.com", want: Settings{Type: GitLab, APIURL: "https://gitlab.com/api/v4", Token: "cfg-token"}},
+		{source: "git.mycompany.com", want: Settings{Type: Gitea, APIURL: "https://git.mycompany.com/api/v1", Token: "gitea-token", Protocol: "https"}},
+		{source: "ghe.mycompany.com", want: Settings{Type: GitHub, APIURL: "https://ghe.mycompany.com/custom", Token: "t"}},
+		{source: "github.com", wantErr: "set GITHUB_TOKEN"},
+		{source: "example.com", wantErr: "unknown forge for example.com"},
+		{source: "code.mycompany.com", wantErr: `unsupported forge type "bitbucket"`},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.source, cfg)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Resolve(%q) error = %v, want %q", tt.source, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Resolve(%q): %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Resolve(%q) = %+v, want %+v", tt.source, got, tt.want)
+		}
+	}
+
+	// The environment wins over the config file
+	t.Setenv("GH_TOKEN", "env-token")
+	got, err := Resolve("github.com", cfg)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := Settings{Type: GitHub, APIURL: "https://api.github.com", Token: "env-token"}
+	if got != want {
+		t.Errorf("Resolve(github.com) = %+v, want %+v", got, want)
+	}
+}
+
+func TestGitHubCreateRepo(t *testing.T) {
+	repoJSON := `{"full_name":"%s/api","ssh_url":"git@github.com:%s/api.git","clone_url":"https://github.com/%s/api.git","html_url":"https://github.com/%s/api"}`
+	srv, seen := stubServer(t, map[string]string{
+		"GET /user":                  `{"login":"dsaiztc"}`,
+		"POST /user/repos":           strings.ReplaceAll(repoJSON, "%s", "dsaiztc"),
+		"POST /orgs/mycompany/repos": strings.ReplaceAll(repoJSON, "%s", "mycompany"),
+	})
+	client := New(Settings{Type: GitHub, APIURL: srv.URL, Token: "secret"})
+
+	repo, err := client.CreateRepo(context.Background(), "mycompany", "api", true)
+	if err != nil {
+		t.Fatalf("CreateRepo: %v", err)
+	}
+	want := &Repo{
+		Path:     "mycompany/api",
+		SSHURL:   "git@github.com:mycompany/api.git",
+		HTTPSURL: "https://github.com/mycompany/api.git",
+		WebURL:   "https://github.com/mycompany/api",
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Errorf("CreateRepo = %+v, want %+v", repo, want)
+	}
+	last := (*seen)[len(*seen)-1]
+	if last.Path != "/orgs/mycompany/repos" || last.Auth != "Bearer secret" {
+		t.Errorf("request = %+v, want POST /orgs/mycompany/repos with a bearer token", last)
+	}
+	if !reflect.DeepEqual(last.Body, map[string]any{"name": "api", "private": true}) {
+		t.Errorf("body = %v", last.Body)
+	}
+
+	// The authenticated user's own namespace uses /user/repos
+	if _, err := client.CreateRepo(context.Background(), "DSaizTC", "api", false); err != nil {
+		t.Fatalf("CreateRepo: %v", err)
+	}
+	last = (*seen)[len(*seen)-1]
+	if last.Path != "/user/repos" || last.Body["private"] != false {
+		t.Errorf("request = %+v, want a public POST /user/repos", last)
+	}
+}
+
+func TestGiteaCreateRepo(t *testing.T) {
+	srv, seen := stubServer(t, map[string]string{
+		"GET /user":        `{"login":"dsaiztc"}`,
+		"POST /user/repos": `{"full_name":"dsaiztc/api","ssh_url":"ssh://git@git.example.com/dsaiztc/api.git","clone_url":"https://git.example.com/dsaiztc/api.git"}`,
+	})
+	client := New(Settings{Type: Gitea, APIURL: srv.URL, Token: "secret"})
+
+	repo, err := client.CreateRepo(context.Background(), "dsaiztc", "api", true)
+	if err != nil {
+		t.Fatalf("CreateRepo: %v", err)
+	}
+	if got := repo.CloneURL("https"); got != "https://git.example.com/dsaiztc/api.git" {
+		t.Errorf("CloneURL(https) = %q", got)
+	}
+	if got := repo.CloneURL(""); got != "ssh://git@git.example.com/dsaiztc/api.git" {
+		t.Errorf("CloneURL(\"\") = %q", got)
+	}
+	if auth := (*seen)[0].Auth; auth != "token secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "token secret")
+	}
+}
+
+func TestGitLabCreateRepo(t *testing.T) {
+	srv, seen := stubServer(t, map[string]string{
+		"GET /namespaces/mycompany%2Fplatform": `{"id":42}`,
+		"POST /projects":                       `{"path_with_namespace":"mycompany/platform/api","ssh_url_to_repo":"git@gitlab.com:mycompany/platform/api.git","http_url_to_repo":"https://gitlab.com/mycompany/platform/api.git","web_url":"https://gitlab.com/mycompany/platform/api"}`,
+	})
+	client := New(Settings{Type: GitLab, APIURL: srv.URL, Token: "secret"})
+
+	repo, err := client.CreateRepo(context.Background(), "mycompany/platform", "api", true)
+	if err != nil {
+		t.Fatalf("CreateRepo: %v", err)
+	}
+	if repo.Path != "mycompany/platform/api" || repo.SSHURL != "git@gitlab.com:mycompany/platform/api.git" {
+		t.Errorf("CreateRepo = %+v", repo)
+	}
+	last := (*seen)[len(*seen)-1]
+	if last.Auth != "secret" {
+		t.Errorf("PRIVATE-TOKEN = %q, want %q", last.Auth, "secret")
+	}
+	wantBody := map[string]any{"name": "api", "path": "api", "namespace_id": float64(42), "visibility": "private"}
+	if !reflect.DeepEqual(last.Body, wantBody) {
+		t.Errorf("body = %v, want %v", last.Body, wantBody)
+	}
+}
+
+// repoPage returns a JSON array of n GitHub repos named <org>/repo-<start+i>.
+func repoPage(org string, start, n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"full_name":"%s/repo-%d"}`, org, start+i)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestGitHubListRepos(t *testing.T) {
+	srv, _ := stubServer(t, map[string]string{
+		"GET /user": `{"login":"dsaiztc"}`,
+		"GET /orgs/mycompany/repos?type=all&per_page=100&page=1":  repoPage("mycompany", 0, 100),
+		"GET /orgs/mycompany/repos?type=all&per_page=100&page=2":  `[{"full_name":"mycompany/old","archived":true,"fork":true,"topics":["go"]}]`,
+		"GET /users/someone/repos?type=owner&per_page=100&page=1": repoPage("someone", 0, 1),
+		"GET /user/repos?affiliation=owner&per_page=100&page=1":   repoPage("dsaiztc", 0, 2),
+	})
+	client := New(Settings{Type: GitHub, APIURL: srv.URL, Token: "secret"})
+
+	repos, err := client.ListRepos(context.Background(), "mycompany")
+	if err != nil {
+		t.Fatalf("ListRepos: %v", err)
+	}
+	if len(repos) != 101 {
+		t.Fatalf("ListRepos returned %d repos, want 101", len(repos))
+	}
+	want := Repo{Path: "mycompany/old", Archived: true, Fork: true, Topics: []string{"go"}}
+	if !reflect.DeepEqual(repos[100], want) {
+		t.Errorf("last repo = %+v, want %+v", repos[100], want)
+	}
+
+	// A user that is not an org
+	repos, err = client.ListRepos(context.Background(), "someone")
+	if err != nil || len(repos) != 1 {
+		t.Errorf("ListRepos(someone) = %v, %v; want the user's repo", repos, err)
+	}
+
+	// The authenticated user's own repos include private ones
+	repos, err = client.ListRepos(context.Background(), "dsaiztc")
+	if err != nil || len(repos) != 2 {
+		t.Errorf("ListRepos(dsaiztc) = %v, %v; want 2 repos", repos, err)
+	}
+}
+
+func TestGiteaListRepos(t *testing.T) {
+	srv, _ := stubServer(t, map[string]string{
+		"GET /user": `{"login":"dsaiztc"}`,
+		"GET /users/someone/repos?limit=50&page=1": repoPage("someone", 0, 3),
+		"GET /user/repos?limit=50&page=1":          `[{"full_name":"dsaiztc/private"},{"full_name":"mycompany/shared"},{"full_name":"DSaiztc/dotfiles"}]`,
+	})
+	client := New(Settings{Type: Gitea, APIURL: srv.URL, Token: "secret"})
+
+	repos, err := client.ListRepos(context.Background(), "someone")
+	if err != nil {
+		t.Fatalf("ListRepos: %v", err)
+	}
+	if len(repos) != 3 || repos[2].Path != "someone/repo-2" {
+		t.Errorf("ListRepos = %+v, want someone/repo-0..2", repos)
+	}
+
+	// The authenticated user's own repos include private ones, but not the
+	// repos of others they collaborate on
+	repos, err = client.ListRepos(context.Background(), "dsaiztc")
+	if err != nil {
+		t.Fatalf("ListRepos(dsaiztc): %v", err)
+	}
+	if len(repos) != 2 || repos[0].Path != "dsaiztc/private" || repos[1].Path != "DSaiztc/dotfiles" {
+		t.Errorf("ListRepos(dsaiztc) = %+v, want dsaiztc/private and DSaiztc/dotfiles", repos)
+	}
+}
+
+func TestGitLabListRepos(t *testing.T) {
+	srv, _ := stubServer(t, map[string]string{
+		"GET /groups/mycompany/projects?include_subgroups=true&per_page=100&page=1": `[
+			{"path_with_namespace":"mycompany/api","topics":["go"]},
+			{"path_with_namespace":"mycompany/platform/infra","archived":true},
+			{"path_with_namespace":"mycompany/fork","forked_from_project":{"id":1}}
+		]`,
+		"GET /users/someone/projects?per_page=100&page=1": `[{"path_with_namespace":"someone/dotfiles"}]`,
+	})
+	client := New(Settings{Type: GitLab, APIURL: srv.URL, Token: "secret"})
+
+	repos, err := client.ListRepos(context.Background(), "mycompany")
+	if err != nil {
+		t.Fatalf("ListRepos: %v", err)
+	}
+	want := []Repo{
+		{Path: "mycompany/api", Topics: []string{"go"}},
+		{Path: "mycompany/platform/infra", Archived: true},
+		{Path: "mycompany/fork", Fork: true},
+	}
+	if !reflect.DeepEqual(repos, want) {
+		t.Errorf("ListRepos = %+v, want %+v", repos, want)
+	}
+
+	repos, err = client.ListRepos(context.Background(), "someone")
+	if err != nil || len(repos) != 1 || repos[0].Path != "someone/dotfiles" {
+		t.Errorf("ListRepos(someone) = %+v, %v; want the user's project", repos, err)
+	}
+}
+
+func TestCreateRepo_APIError(t *testing.T) {
+	srv, _ := stubServer(t, map[string]string{"GET /user": `{"login":"dsaiztc"}`})
+	client := New(Settings{Type: GitHub, APIURL: srv.URL, Token: "secret"})
+
+	_, err := client.CreateRepo(context.Background(), "mycompany", "api", true)
+	if err == nil {
+		t.Fatal("CreateRepo succeeded, want an error")
+	}
+	want := "could not create mycompany/api: POST /orgs/mycompany/repos: 404 Not Found: Not Found"
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestAPIMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"message":"Bad credentials"}`, ": Bad credentials"},
+		{`{"message":{"name":["has already been taken"]}}`, ": map[name:[has already been taken]]"},
+		{`{"error":"insufficient_scope"}`, ": insufficient_scope"},
+		{`{}`, ""},
+		{`<html>`, ""},
+	}
+	for _, tt := range tests {
+		if got := apiMessage([]byte(tt.body)); got != tt.want {
+			t.Errorf("apiMessage(%s) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package forge
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// giteaClient talks to the Gitea (or Forgejo) REST API, which mirrors
+// GitHub's for the endpoints used here.
+type giteaClient struct {
+	*api
+}
+
+func (c *giteaClient) CreateRepo(ctx context.Context, org, name string, private bool) (*Repo, error) {
+	return createUserOrOrgRepo(ctx, c.api, org, name, private)
+}
+
+func (c *giteaClient) ListRepos(ctx context.Context, org string) ([]Repo, error) {
+	login, err := authenticatedUser(ctx, c.api)
+	if err != nil {
+		return nil, err
+	}
+	// Gitea caps pages at 50 items by default
+	if strings.EqualFold(org, login) {
+		// /users/{user}/repos only lists public repos, even your own, while
+		// /user/repos also lists the repos the user collaborates on
+		repos, err := listRepos(ctx, c.api, org, "/user/repos", "", "limit", 50)
+		return slices.DeleteFunc(repos, func(r Repo) bool {
+			owner, _, _ := strings.Cut(r.Path, "/")
+			return !strings.EqualFold(owner, login)
+		}), err
+	}
+	return listRepos(ctx, c.api, org,
+		"/orgs/"+url.PathEscape(org)+"/repos",
+		"/users/"+url.PathEscape(org)+"/repos",
+		"limit", 50)
+}
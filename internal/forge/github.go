@@ -0,0 +1,106 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// githubClient talks to the GitHub REST API (github.com or Enterprise).
+type githubClient struct {
+	*api
+}
+
+// githubRepo is a repository in GitHub and Gitea API responses.
+type githubRepo struct {
+	FullName string   `json:"full_name"`
+	SSHURL   string   `json:"ssh_url"`
+	CloneURL string   `json:"clone_url"`
+	HTMLURL  string   `json:"html_url"`
+	Archived bool     `json:"archived"`
+	Fork     bool     `json:"fork"`
+	Topics   []string `json:"topics"`
+}
+
+func (r githubRepo) repo() *Repo {
+	return &Repo{
+		Path:     r.FullName,
+		SSHURL:   r.SSHURL,
+		HTTPSURL: r.CloneURL,
+		WebURL:   r.HTMLURL,
+		Archived: r.Archived,
+		Fork:     r.Fork,
+		Topics:   r.Topics,
+	}
+}
+
+func (c *githubClient) CreateRepo(ctx context.Context, org, name string, private bool) (*Repo, error) {
+	return createUserOrOrgRepo(ctx, c.api, org, name, private)
+}
+
+func (c *githubClient) ListRepos(ctx context.Context, org string) ([]Repo, error) {
+	login, err := authenticatedUser(ctx, c.api)
+	if err != nil {
+		return nil, err
+	}
+	// /users/{user}/repos only lists public repos, even your own
+	if strings.EqualFold(org, login) {
+		return listRepos(ctx, c.api, org, "/user/repos?affiliation=owner", "", "per_page", 100)
+	}
+	return listRepos(ctx, c.api, org,
+		"/orgs/"+url.PathEscape(org)+"/repos?type=all",
+		"/users/"+url.PathEscape(org)+"/repos?type=owner",
+		"per_page", 100)
+}
+
+// authenticatedUser returns the login of the token's user on GitHub or Gitea.
+func authenticatedUser(ctx context.Context, a *api) (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if err := a.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return "", fmt.Errorf("could not get authenticated user: %w", err)
+	}
+	return user.Login, nil
+}
+
+// createUserOrOrgRepo creates a repo through the endpoints GitHub and Gitea
+// share: /user/repos for the authenticated user's namespace and
+// /orgs/{org}/repos for an organization.
+func createUserOrOrgRepo(ctx context.Context, a *api, org, name string, private bool) (*Repo, error) {
+	login, err := authenticatedUser(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	path := "/orgs/" + url.PathEscape(org) + "/repos"
+	if strings.EqualFold(org, login) {
+		path = "/user/repos"
+	}
+	body := map[string]any{"name": name, "private": private}
+
+	var created githubRepo
+	if err := a.do(ctx, http.MethodPost, path, body, &created); err != nil {
+		return nil, fmt.Errorf("could not create %s/%s: %w", org, name, err)
+	}
+	return created.repo(), nil
+}
+
+// listRepos lists the GitHub or Gitea repos at path, falling back to
+// fallback (if set) when path does not exist, as for users instead of orgs.
+func listRepos(ctx context.Context, a *api, org, path, fallback, limitParam string, limit int) ([]Repo, error) {
+	list, err := listAll[githubRepo](ctx, a, path, limitParam, limit)
+	if err != nil && fallback != "" && isNotFound(err) {
+		list, err = listAll[githubRepo](ctx, a, fallback, limitParam, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list repos of %s: %w", org, err)
+	}
+	repos := make([]Repo, len(list))
+	for i, r := range list {
+		repos[i] = *r.repo()
+	}
+	return repos, nil
+}
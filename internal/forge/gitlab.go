@@ -0,0 +1,75 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// gitlabClient talks to the GitLab REST API.
+type gitlabClient struct {
+	*api
+}
+
+// gitlabProject is a project in GitLab API responses.
+type gitlabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+	SSHURL            string `json:"ssh_url_to_repo"`
+	HTTPURL           string `json:"http_url_to_repo"`
+	WebURL            string `json:"web_url"`
+	Archived          bool   `json:"archived"`
+	// ForkedFrom is only present (non-null) on forks
+	ForkedFrom *struct{} `json:"forked_from_project"`
+	Topics     []string  `json:"topics"`
+}
+
+func (p gitlabProject) repo() *Repo {
+	return &Repo{
+		Path:     p.PathWithNamespace,
+		SSHURL:   p.SSHURL,
+		HTTPSURL: p.HTTPURL,
+		WebURL:   p.WebURL,
+		Archived: p.Archived,
+		Fork:     p.ForkedFrom != nil,
+		Topics:   p.Topics,
+	}
+}
+
+func (c *gitlabClient) CreateRepo(ctx context.Context, org, name string, private bool) (*Repo, error) {
+	// org is a user or (sub)group namespace path; projects are created by its id
+	var ns struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/namespaces/"+url.PathEscape(org), nil, &ns); err != nil {
+		return nil, fmt.Errorf("could not find namespace %s: %w", org, err)
+	}
+
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	body := map[string]any{"name": name, "path": name, "namespace_id": ns.ID, "visibility": visibility}
+
+	var created gitlabProject
+	if err := c.do(ctx, http.MethodPost, "/projects", body, &created); err != nil {
+		return nil, fmt.Errorf("could not create %s/%s: %w", org, name, err)
+	}
+	return created.repo(), nil
+}
+
+func (c *gitlabClient) ListRepos(ctx context.Context, org string) ([]Repo, error) {
+	ns := url.PathEscape(org)
+	list, err := listAll[gitlabProject](ctx, c.api, "/groups/"+ns+"/projects?include_subgroups=true", "per_page", 100)
+	if isNotFound(err) {
+		list, err = listAll[gitlabProject](ctx, c.api, "/users/"+ns+"/projects", "per_page", 100)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not list repos of %s: %w", org, err)
+	}
+	repos := make([]Repo, len(list))
+	for i, p := range list {
+		repos[i] = *p.repo()
+	}
+	return repos, nil
+}
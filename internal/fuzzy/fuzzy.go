@@ -20,6 +20,7 @@ var (
 	selectedStyle = renderer.NewStyle().Foreground(lipgloss.Color("212")).Bold(true)
 	normalStyle   = renderer.NewStyle().Foreground(lipgloss.Color("252"))
 	promptStyle   = renderer.NewStyle().Foreground(lipgloss.Color("39"))
+	helpStyle     = renderer.NewStyle().Foreground(lipgloss.Color("241"))
 )
 
 type model struct {
@@ -29,6 +30,11 @@ type model struct {
 	cursor    int
 	selected  string
 	cancelled bool
+	width     int // terminal width, 0 until known
+
+	// In multi-select mode, chosen holds the items toggled with Tab
+	multi  bool
+	chosen map[string]bool
 }
 
 func newModel(items []string) model {
@@ -41,6 +47,7 @@ func newModel(items []string) model {
 		textInput: ti,
 		items:     items,
 		filtered:  items,
+		chosen:    make(map[string]bool),
 	}
 }
 
@@ -50,6 +57,9 @@ func (m model) Init() tea.Cmd {
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC, tea.KeyEsc:
@@ -70,6 +80,29 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.cursor++
 			}
 			return m, nil
+		case tea.KeyTab:
+			if !m.multi || len(m.filtered) == 0 {
+				return m, nil
+			}
+			item := m.filtered[m.cursor]
+			if m.chosen[item] {
+				delete(m.chosen, item)
+			} else {
+				m.chosen[item] = true
+			}
+			// Move on so several items can be toggled in a row
+			if m.cursor < len(m.filtered)-1 {
+				m.cursor++
+			}
+			return m, nil
+		case tea.KeyCtrlA:
+			if !m.multi {
+				break // the text input moves to the start of the line
+			}
+			for _, item := range m.filtered {
+				m.chosen[item] = true
+			}
+			return m, nil
 		}
 	}
 
@@ -101,6 +134,10 @@ func (m model) View() string {
 
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n")
+	if m.multi {
+		b.WriteString(helpStyle.Render(fmt.Sprintf("  %d/%d selected  tab: toggle  ctrl+a: all  enter: confirm", len(m.chosen), len(m.items))))
+		b.WriteString("\n")
+	}
 
 	if len(m.filtered) == 0 {
 		b.WriteString("  no matches\n")
@@ -117,11 +154,20 @@ func (m model) View() string {
 		end = len(m.filtered)
 	}
 
+	// Cut long items (e.g. dev grep matches) at the terminal edge so each stays on one line
+	selected, normal := selectedStyle, normalStyle
+	if m.width > 2 {
+		selected, normal = selected.MaxWidth(m.width-2), normal.MaxWidth(m.width-2)
+	}
 	for i := start; i < end; i++ {
+		mark := "  "
+		if m.chosen[m.filtered[i]] {
+			mark = selectedStyle.Render("● ")
+		}
 		if i == m.cursor {
-			b.WriteString(fmt.Sprintf("  %s\n", selectedStyle.Render(m.filtered[i])))
+			b.WriteString(fmt.Sprintf("%s%s\n", mark, selected.Render(m.filtered[i])))
 		} else {
-			b.WriteString(fmt.Sprintf("  %s\n", normalStyle.Render(m.filtered[i])))
+			b.WriteString(fmt.Sprintf("%s%s\n", mark, normal.Render(m.filtered[i])))
 		}
 	}
 
@@ -135,13 +181,67 @@ func Run(items []string) (string, error) {
 		return "", nil
 	}
 
+	result, err := run(newModel(items))
+	if err != nil || result.cancelled {
+		return "", err
+	}
+	return result.selected, nil
+}
+
+// RunMulti is like Run but lets the user pick several items: Tab toggles the
+// item under the cursor and Ctrl-A selects every item matching the query.
+// Items whose entry in selected (which may be nil) is true start toggled on.
+// Returns the selected items in their original order, or just the one under
+// the cursor if none were toggled. Returns nil if the user cancels.
+func RunMulti(items []string, selected []bool) ([]string, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	result, err := run(newMultiModel(items, selected))
+	if err != nil || result.cancelled {
+		return nil, err
+	}
+	return result.choice(), nil
+}
+
+// newMultiModel returns a multi-select model with the items whose entry in
+// selected is true already toggled on.
+func newMultiModel(items []string, selected []bool) model {
 	m := newModel(items)
+	m.multi = true
+	for i, on := range selected {
+		if on && i < len(items) {
+			m.chosen[items[i]] = true
+		}
+	}
+	return m
+}
+
+// choice returns the items picked in multi-select mode.
+func (m model) choice() []string {
+	if len(m.chosen) == 0 {
+		if m.selected == "" {
+			return nil
+		}
+		return []string{m.selected}
+	}
+	var chosen []string
+	for _, item := range m.items {
+		if m.chosen[item] {
+			chosen = append(chosen, item)
+		}
+	}
+	return chosen
+}
 
+// run runs the finder until the user confirms or cancels.
+func run(m model) (model, error) {
 	// Open /dev/tty directly for input so the TUI works
 	// even when stdout is captured by the shell wrapper's $()
 	tty, err := os.Open("/dev/tty")
 	if err != nil {
-		return "", fmt.Errorf("could not open /dev/tty: %w", err)
+		return m, fmt.Errorf("could not open /dev/tty: %w", err)
 	}
 	defer tty.Close()
 
@@ -153,12 +253,7 @@ func Run(items []string) (string, error) {
 
 	finalModel, err := p.Run()
 	if err != nil {
-		return "", fmt.Errorf("fuzzy finder error: %w", err)
+		return m, fmt.Errorf("fuzzy finder error: %w", err)
 	}
-
-	result := finalModel.(model)
-	if result.cancelled {
-		return "", nil
-	}
-	return result.selected, nil
+	return finalModel.(model), nil
 }
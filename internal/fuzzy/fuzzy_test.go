@@ -0,0 +1,69 @@
+package fuzzy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press feeds keys to m as if typed.
+func press(m model, keys ...tea.KeyMsg) model {
+	for _, k := range keys {
+		next, _ := m.Update(k)
+		m = next.(model)
+	}
+	return m
+}
+
+func typed(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestMultiSelect(t *testing.T) {
+	newMulti := func() model {
+		return newMultiModel([]string{"feature-a", "fix-b", "feature-c", "chore-d"}, nil)
+	}
+
+	// Tab toggles and moves down, so a second Tab picks the next item
+	m := press(newMulti(), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyTab})
+	if got, want := m.choice(), []string{"feature-a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after toggling twice = %q, want %q", got, want)
+	}
+	if view := m.View(); !strings.Contains(view, "1/4 selected") {
+		t.Errorf("view has no selected count:\n%s", view)
+	}
+
+	// Ctrl-A selects only what the query matches, kept in original order
+	m = press(newMulti(), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyTab}, typed("feat"), tea.KeyMsg{Type: tea.KeyCtrlA})
+	if got, want := m.choice(), []string{"feature-a", "fix-b", "feature-c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after ctrl+a = %q, want %q", got, want)
+	}
+
+	// Preselected items can be toggled off like any other
+	m = newMultiModel([]string{"feature-a", "fix-b", "feature-c", "chore-d"}, []bool{false, true, false, true})
+	if view := m.View(); !strings.Contains(view, "2/4 selected") {
+		t.Errorf("view does not count preselected items:\n%s", view)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	if got, want := m.choice(), []string{"chore-d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after untoggling a preselected item = %q, want %q", got, want)
+	}
+
+	// Without toggling, Enter picks the item under the cursor
+	m = press(newMulti(), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if got, want := m.choice(), []string{"fix-b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("enter without toggling = %q, want %q", got, want)
+	}
+}
+
+func TestSingleSelectIgnoresMultiKeys(t *testing.T) {
+	m := press(newModel([]string{"a", "b"}), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyCtrlA}, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.chosen) != 0 || m.selected != "a" {
+		t.Errorf("chosen = %v, selected = %q; want no multi-selection and %q", m.chosen, m.selected, "a")
+	}
+	if strings.Contains(m.View(), "selected") {
+		t.Error("single-select view shows a selected count")
+	}
+}
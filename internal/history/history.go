@@ -0,0 +1,194 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dsaiztc/dev/internal/atomicfile"
+)
+
+// halfLife is how long it takes for a visit to count half as much.
+const halfLife = 7 * 24 * time.Hour
+
+// maxEntries bounds the history file; the lowest-scoring entries are dropped.
+const maxEntries = 1000
+
+// Entry records how often and how recently a directory was selected.
+type Entry struct {
+	Path      string    `json:"path"`
+	Visits    int       `json:"visits"`
+	LastVisit time.Time `json:"last_visit"`
+}
+
+// Score returns the entry's frecency at the given time: the visit count
+// decayed exponentially by the time since the last visit.
+func (e Entry) Score(now time.Time) float64 {
+	age := now.Sub(e.LastVisit)
+	if age < 0 {
+		age = 0
+	}
+	return float64(e.Visits) * math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// History holds the visit entries keyed by absolute path.
+type History struct {
+	Entries map[string]*Entry `json:"entries"`
+}
+
+// Path returns the history file path (~/.local/share/dev/history.json).
+func Path() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".local", "share", "dev", "history.json"), nil
+}
+
+// Load reads the history file. A missing file yields an empty history.
+func Load() (*History, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFrom(path)
+}
+
+// LoadFrom reads a history from the given path. A missing file yields an empty history.
+func LoadFrom(path string) (*History, error) {
+	h := &History{Entries: make(map[string]*Entry)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return h, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("could not parse history: %w", err)
+	}
+	if h.Entries == nil {
+		h.Entries = make(map[string]*Entry)
+	}
+	return h, nil
+}
+
+// SaveTo writes the history to the given path, creating parent directories as needed.
+func (h *History) SaveTo(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create history directory: %w", err)
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("could not marshal history: %w", err)
+	}
+	// Overlapping shells each record visits; replace the file atomically so
+	// none of them can leave it truncated
+	if err := atomicfile.Write(path, data, 0o644); err != nil {
+		return fmt.Errorf("could not write history: %w", err)
+	}
+	return nil
+}
+
+// Record registers a visit to path at the given time.
+func (h *History) Record(path string, now time.Time) {
+	e, ok := h.Entries[path]
+	if !ok {
+		e = &Entry{Path: path}
+		h.Entries[path] = e
+	}
+	e.Visits++
+	e.LastVisit = now
+
+	if len(h.Entries) <= maxEntries {
+		return
+	}
+
+	// Drop the lowest-scoring entries, never the one just visited
+	ranked := h.Top(0, now)
+	for i := len(ranked) - 1; i >= 0 && len(h.Entries) > maxEntries; i-- {
+		if ranked[i].Path != path {
+			delete(h.Entries, ranked[i].Path)
+		}
+	}
+}
+
+// Top returns up to n entries ordered by descending score. n <= 0 returns all.
+func (h *History) Top(n int, now time.Time) []Entry {
+	entries := make([]Entry, 0, len(h.Entries))
+	for _, e := range h.Entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		si, sj := entries[i].Score(now), entries[j].Score(now)
+		if si != sj {
+			return si > sj
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// ScoresUnder returns the scores of entries below root, keyed by path
+// relative to root (the form repos.Discover returns).
+func (h *History) ScoresUnder(root string, now time.Time) map[string]float64 {
+	scores := make(map[string]float64)
+	for path, e := range h.Entries {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		scores[rel] = e.Score(now)
+	}
+	return scores
+}
+
+// Visit records a visit to path in the default history file.
+func Visit(path string) error {
+	historyPath, err := Path()
+	if err != nil {
+		return err
+	}
+	h, err := LoadFrom(historyPath)
+	if err != nil {
+		return err
+	}
+	h.Record(path, time.Now())
+	return h.SaveTo(historyPath)
+}
+
+// Scores returns a path → score lookup for the given absolute paths, loaded
+// from the default history file. Missing history yields no scores.
+func Scores(paths []string) map[string]float64 {
+	scores := make(map[string]float64)
+	h, err := Load()
+	if err != nil {
+		return scores
+	}
+	now := time.Now()
+	for _, p := range paths {
+		if e, ok := h.Entries[p]; ok {
+			scores[p] = e.Score(now)
+		}
+	}
+	return scores
+}
+
+// ScoresUnderRoot is like ScoresUnder but reads the default history file.
+// Missing history yields no scores.
+func ScoresUnderRoot(root string) map[string]float64 {
+	h, err := Load()
+	if err != nil {
+		return map[string]float64{}
+	}
+	return h.ScoresUnder(root, time.Now())
+}
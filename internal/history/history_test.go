@@ -0,0 +1,132 @@
+package history
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEntryScore_Decay(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	fresh := Entry{Visits: 4, LastVisit: now}
+	if got := fresh.Score(now); got != 4 {
+		t.Errorf("fresh Score() = %v, want 4", got)
+	}
+
+	weekOld := Entry{Visits: 4, LastVisit: now.Add(-halfLife)}
+	if got := weekOld.Score(now); math.Abs(got-2) > 1e-9 {
+		t.Errorf("one half-life old Score() = %v, want 2", got)
+	}
+
+	future := Entry{Visits: 3, LastVisit: now.Add(time.Hour)}
+	if got := future.Score(now); got != 3 {
+		t.Errorf("future Score() = %v, want 3", got)
+	}
+}
+
+func TestRecordAndTop(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	h := &History{Entries: make(map[string]*Entry)}
+
+	// Frequent but stale
+	for i := 0; i < 5; i++ {
+		h.Record("/src/github.com/acme/old", now.Add(-30*24*time.Hour))
+	}
+	// Less frequent but recent
+	h.Record("/src/github.com/acme/new", now)
+	h.Record("/src/github.com/acme/new", now)
+	h.Record("/src/github.com/acme/once", now.Add(-time.Hour))
+
+	top := h.Top(0, now)
+	want := []string{
+		"/src/github.com/acme/new",
+		"/src/github.com/acme/once",
+		"/src/github.com/acme/old",
+	}
+	if len(top) != len(want) {
+		t.Fatalf("Top() returned %d entries, want %d", len(top), len(want))
+	}
+	for i := range want {
+		if top[i].Path != want[i] {
+			t.Errorf("Top()[%d] = %q, want %q", i, top[i].Path, want[i])
+		}
+	}
+	if top[2].Visits != 5 {
+		t.Errorf("expected 5 visits for stale entry, got %d", top[2].Visits)
+	}
+
+	if got := h.Top(1, now); len(got) != 1 {
+		t.Errorf("Top(1) returned %d entries", len(got))
+	}
+}
+
+func TestScoresUnder(t *testing.T) {
+	now := time.Now()
+	h := &History{Entries: make(map[string]*Entry)}
+	h.Record("/home/u/src/github.com/acme/api", now)
+	h.Record("/home/u/src__worktrees/github.com/acme/api__feature", now)
+
+	scores := h.ScoresUnder("/home/u/src", now)
+	if len(scores) != 1 {
+		t.Fatalf("ScoresUnder() = %v, want a single entry", scores)
+	}
+	if scores["github.com/acme/api"] != 1 {
+		t.Errorf("ScoresUnder()[github.com/acme/api] = %v, want 1", scores["github.com/acme/api"])
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history.json")
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	h := &History{Entries: make(map[string]*Entry)}
+	h.Record("/src/github.com/acme/api", now)
+	h.Record("/src/github.com/acme/api", now)
+	if err := h.SaveTo(path); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+
+	got, err := LoadFrom(path)
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	e, ok := got.Entries["/src/github.com/acme/api"]
+	if !ok {
+		t.Fatal("expected entry after round-trip")
+	}
+	if e.Visits != 2 || !e.LastVisit.Equal(now) {
+		t.Errorf("round-trip mismatch: %+v", e)
+	}
+}
+
+func TestLoadFrom_Missing(t *testing.T) {
+	h, err := LoadFrom(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if len(h.Entries) != 0 {
+		t.Errorf("expected empty history, got %v", h.Entries)
+	}
+}
+
+func TestRecord_Bounded(t *testing.T) {
+	now := time.Now()
+	h := &History{Entries: make(map[string]*Entry)}
+	for i := 0; i < maxEntries; i++ {
+		h.Record(fmt.Sprintf("/src/github.com/acme/repo-%04d", i), now)
+	}
+
+	// Sorts after every existing path, so it would lose a plain tie-break
+	latest := "/src/github.com/zzz/latest"
+	h.Record(latest, now)
+
+	if len(h.Entries) != maxEntries {
+		t.Errorf("expected %d entries after overflow, got %d", maxEntries, len(h.Entries))
+	}
+	if _, ok := h.Entries[latest]; !ok {
+		t.Error("expected the most recent visit to be kept")
+	}
+}
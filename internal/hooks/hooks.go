@@ -0,0 +1,89 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// Hook names.
+const (
+	PostWktNew = "post-wkt-new" // after dev wkt new created a worktree
+	PreWktRm   = "pre-wkt-rm"   // before a worktree is removed; failing aborts the removal
+	PostWktRm  = "post-wkt-rm"  // after a worktree was removed
+	PostClone  = "post-clone"   // after dev clone cloned a repo
+	PostNew    = "post-new"     // after dev new created a repo
+)
+
+// Dir is where a repo commits its own hook scripts, relative to its root.
+// A script named after a hook replaces the commands configured for it, but
+// only in repos whose committed hooks the user trusts.
+const Dir = ".dev/hooks"
+
+// Run runs the named hook in dir. If trustScripts is set and dir contains an
+// executable script .dev/hooks/<name>, the script is run; otherwise commands
+// are run as by RunCommands, and an untrusted script is only mentioned. env is
+// added to the environment, and the hook's stdout and stderr both go to
+// stderr so stdout stays free for shell directives. Running nothing is not an
+// error.
+func Run(name, dir string, commands []string, trustScripts bool, env map[string]string, stderr io.Writer) error {
+	script := filepath.Join(dir, Dir, name)
+	info, err := os.Stat(script)
+	switch {
+	case err == nil && !info.IsDir() && !trustScripts:
+		fmt.Fprintf(stderr, "not running committed hook %s: not trusted here\n", script)
+	case err == nil && !info.IsDir():
+		if info.Mode().Perm()&0o111 == 0 {
+			return fmt.Errorf("%s hook %s is not executable", name, script)
+		}
+		return run(name, dir, []*exec.Cmd{exec.Command(script)}, env, stderr)
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("could not read %s hook: %w", name, err)
+	}
+	return RunCommands(name, dir, commands, env, stderr)
+}
+
+// RunCommands runs each of commands in dir with sh -c, stopping at the first
+// failure, like Run but without looking for a committed script. It is used
+// for hooks on freshly cloned repos, whose scripts are never run.
+func RunCommands(name, dir string, commands []string, env map[string]string, stderr io.Writer) error {
+	cmds := make([]*exec.Cmd, len(commands))
+	for i, c := range commands {
+		cmds[i] = exec.Command("sh", "-c", c)
+	}
+	return run(name, dir, cmds, env, stderr)
+}
+
+func run(name, dir string, cmds []*exec.Cmd, env map[string]string, stderr io.Writer) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "running %s hook\n", name)
+	environ := append(os.Environ(), Environ(env)...)
+	for _, cmd := range cmds {
+		cmd.Dir = dir
+		cmd.Env = environ
+		cmd.Stdout = stderr
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook failed: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Environ formats env as sorted KEY=value pairs.
+func Environ(env map[string]string) []string {
+	pairs := make([]string, 0, len(env))
+	for k, v := range env {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
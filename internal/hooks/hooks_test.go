@@ -0,0 +1,117 @@
+package hooks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeScript(t *testing.T, dir, name, body string, perm os.FileMode) {
+	t.Helper()
+	path := filepath.Join(dir, Dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_Commands(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	err := Run(PostWktNew, dir, []string{"echo branch=$DEV_BRANCH", "pwd"}, false, map[string]string{"DEV_BRANCH": "feat"}, &out)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "running post-wkt-new hook") || !strings.Contains(got, "branch=feat") {
+		t.Errorf("output = %q, want hook banner and env-expanded echo", got)
+	}
+	resolved, _ := filepath.EvalSymlinks(dir)
+	if !strings.Contains(got, resolved) {
+		t.Errorf("output = %q, want commands to run in %s", got, resolved)
+	}
+}
+
+func TestRun_StopsAtFirstFailure(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(PreWktRm, t.TempDir(), []string{"exit 3", "echo unreachable"}, false, nil, &out)
+	if err == nil {
+		t.Fatal("expected error from failing command")
+	}
+	if strings.Contains(out.String(), "unreachable") {
+		t.Error("commands after a failure must not run")
+	}
+}
+
+func TestRun_ScriptOverridesCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, PostWktRm, "echo from-script $DEV_REPO", 0o755)
+	var out bytes.Buffer
+
+	if err := Run(PostWktRm, dir, []string{"echo from-config"}, true, map[string]string{"DEV_REPO": "github.com/o/r"}, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "from-script github.com/o/r") || strings.Contains(got, "from-config") {
+		t.Errorf("output = %q, want only the committed script to run", got)
+	}
+}
+
+func TestRun_UntrustedScript(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, PostWktNew, "echo from-script", 0o755)
+	var out bytes.Buffer
+
+	if err := Run(PostWktNew, dir, []string{"echo from-config"}, false, nil, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "from-script") || !strings.Contains(got, "from-config") {
+		t.Errorf("output = %q, want only the configured commands to run", got)
+	}
+	if !strings.Contains(got, "not trusted") {
+		t.Errorf("output = %q, want a note about the skipped script", got)
+	}
+}
+
+func TestRun_NonExecutableScript(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, PreWktRm, "true", 0o644)
+	if err := Run(PreWktRm, dir, nil, true, nil, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for a non-executable hook script")
+	}
+}
+
+func TestRun_Nothing(t *testing.T) {
+	var out bytes.Buffer
+	if err := Run(PostWktNew, t.TempDir(), nil, false, nil, &out); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q, want nothing when no hook is configured", out.String())
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	got := Environ(map[string]string{"B": "2", "A": "1"})
+	if strings.Join(got, " ") != "A=1 B=2" {
+		t.Errorf("Environ() = %v, want sorted pairs", got)
+	}
+}
+
+func TestRunCommands_IgnoresScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, PostClone, "echo from-script", 0o755)
+	var out bytes.Buffer
+
+	if err := RunCommands(PostClone, dir, []string{"echo from-config"}, nil, &out); err != nil {
+		t.Fatalf("RunCommands: %v", err)
+	}
+	if got := out.String(); strings.Contains(got, "from-script") || !strings.Contains(got, "from-config") {
+		t.Errorf("output = %q, want only the configured commands to run", got)
+	}
+}
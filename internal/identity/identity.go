@@ -0,0 +1,73 @@
+package identity
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+// Mismatch is a git config key whose effective value in a repo differs from
+// the one its identity profile wants.
+type Mismatch struct {
+	Key  string // e.g. "user.email"
+	Want string
+	Got  string // empty when unset
+}
+
+// setting is a git config key and the value an identity wants for it.
+type setting struct {
+	key, value string
+}
+
+// settings returns the git config an identity sets. Empty fields are left
+// alone, so a profile without a signing key keeps whatever is configured.
+func settings(id config.Identity) []setting {
+	var s []setting
+	for _, kv := range []setting{
+		{"user.name", id.Name},
+		{"user.email", id.Email},
+		{"user.signingkey", id.SigningKey},
+	} {
+		if kv.value != "" {
+			s = append(s, kv)
+		}
+	}
+	return s
+}
+
+// Apply writes the identity to the repo's local git config.
+func Apply(dir string, id config.Identity) error {
+	for _, s := range settings(id) {
+		cmd := exec.Command("git", "config", "--local", s.key, s.value)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("could not set %s: %s", s.key, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// Check compares the repo's effective git config (local, global and
+// included files) with the identity and returns the keys that differ.
+func Check(dir string, id config.Identity) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, s := range settings(id) {
+		cmd := exec.Command("git", "config", "--get", s.key)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			// Exit code 1 means the key is unset; anything else is a real failure
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+				return nil, fmt.Errorf("could not read %s: %w", s.key, err)
+			}
+		}
+		if got := strings.TrimSpace(string(out)); got != s.value {
+			mismatches = append(mismatches, Mismatch{Key: s.key, Want: s.value, Got: got})
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,65 @@
+package identity
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+// initRepo creates a git repo with an isolated global config.
+func initRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestCheckAndApply(t *testing.T) {
+	dir := initRepo(t)
+	if err := exec.Command("git", "-C", dir, "config", "user.email", "me@example.com").Run(); err != nil {
+		t.Fatal(err)
+	}
+	id := config.Identity{Profile: "work", Name: "Me", Email: "me@mycompany.com", SigningKey: "ABC123"}
+
+	got, err := Check(dir, id)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := []Mismatch{
+		{Key: "user.name", Want: "Me", Got: ""},
+		{Key: "user.email", Want: "me@mycompany.com", Got: "me@example.com"},
+		{Key: "user.signingkey", Want: "ABC123", Got: ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() = %+v, want %+v", got, want)
+	}
+
+	if err := Apply(dir, id); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got, err := Check(dir, id); err != nil || len(got) != 0 {
+		t.Errorf("Check() after Apply = %+v, %v; want no mismatches", got, err)
+	}
+}
+
+func TestCheck_SkipsEmptyFields(t *testing.T) {
+	dir := initRepo(t)
+	if err := exec.Command("git", "-C", dir, "config", "user.signingkey", "OTHER").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(dir, config.Identity{Email: "me@example.com"}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	got, err := Check(dir, config.Identity{Email: "me@example.com"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("Check() = %+v, %v; want fields absent from the profile ignored", got, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package manifest reads and writes workspace manifests: JSON or YAML files
+// listing the repos everyone on a team should have cloned.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/repourl"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the conventional manifest file name.
+const DefaultFile = "dev.json"
+
+// Manifest lists the repos of a workspace.
+type Manifest struct {
+	Repos []Repo `json:"repos" yaml:"repos"`
+}
+
+// Repo is a repo in a manifest. It is cloned into the standard
+// source/org/project layout derived from its URL.
+type Repo struct {
+	URL       string   `json:"url" yaml:"url"`
+	Branch    string   `json:"branch,omitempty" yaml:"branch,omitempty"`       // checked out after cloning instead of the default branch
+	Worktrees []string `json:"worktrees,omitempty" yaml:"worktrees,omitempty"` // branches that get a linked worktree
+}
+
+// Path returns the repo's location under the source root.
+func (r Repo) Path() (repourl.RepoPath, error) {
+	return repourl.Parse(r.URL)
+}
+
+// CheckPath fails if path names a file in a format other than JSON or YAML,
+// judging by its extension. Paths without one (e.g. /dev/stdin) are accepted
+// and read as JSON.
+func CheckPath(path string) error {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case "", ".json", ".yaml", ".yml":
+		return nil
+	default:
+		return fmt.Errorf("manifest %s: unsupported %s file, manifests are JSON or YAML (e.g. %s)", path, ext, DefaultFile)
+	}
+}
+
+// IsYAML reports whether path names a YAML manifest (.yaml or .yml).
+func IsYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Load reads the manifest at path, as YAML if IsYAML and as JSON otherwise
+// (see CheckPath). Every repo must have a parseable URL, and no two repos may
+// map to the same path.
+func Load(path string) (*Manifest, error) {
+	if err := CheckPath(path); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+	var m Manifest
+	if IsYAML(path) {
+		err = yaml.Unmarshal(data, &m)
+	} else {
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse manifest %s: %w", path, err)
+	}
+
+	seen := make(map[string]string)
+	for _, r := range m.Repos {
+		rp, err := r.Path()
+		if err != nil {
+			return nil, fmt.Errorf("invalid repo URL %q in manifest: %w", r.URL, err)
+		}
+		if prev, ok := seen[rp.FullPath()]; ok {
+			return nil, fmt.Errorf("manifest lists %s twice (%s and %s)", rp.FullPath(), prev, r.URL)
+		}
+		seen[rp.FullPath()] = r.URL
+	}
+	return &m, nil
+}
+
+// Write writes m to w as indented JSON.
+func (m *Manifest) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteYAML writes m to w as YAML.
+func (m *Manifest) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// OriginURL returns the URL of the origin remote of the repo at dir as
+// configured, without applying url.<base>.insteadOf rewrites.
+func OriginURL(dir string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no origin remote")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndWrite(t *testing.T) {
+	path := writeManifest(t, `{
+  "repos": [
+    {"url": "git@github.com:mycompany/api.git", "branch": "develop", "worktrees": ["feature-x"]},
+    {"url": "https://gitlab.com/group/sub/tool.git"}
+  ]
+}`)
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := &Manifest{Repos: []Repo{
+		{URL: "git@github.com:mycompany/api.git", Branch: "develop", Worktrees: []string{"feature-x"}},
+		{URL: "https://gitlab.com/group/sub/tool.git"},
+	}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Load = %+v, want %+v", m, want)
+	}
+	rp, _ := m.Repos[1].Path()
+	if rp.FullPath() != "gitlab.com/group/sub/tool" {
+		t.Errorf("Path = %q", rp.FullPath())
+	}
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	again, err := Load(writeManifest(t, buf.String()))
+	if err != nil {
+		t.Fatalf("Load written manifest: %v", err)
+	}
+	if !reflect.DeepEqual(again, m) {
+		t.Errorf("round trip = %+v, want %+v", again, m)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.yaml")
+	content := `repos:
+  - url: git@github.com:mycompany/api.git
+    branch: develop
+    worktrees: [feature-x]
+  - url: https://gitlab.com/group/sub/tool.git
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := &Manifest{Repos: []Repo{
+		{URL: "git@github.com:mycompany/api.git", Branch: "develop", Worktrees: []string{"feature-x"}},
+		{URL: "https://gitlab.com/group/sub/tool.git"},
+	}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Load = %+v, want %+v", m, want)
+	}
+
+	var buf bytes.Buffer
+	if err := m.WriteYAML(&buf); err != nil {
+		t.Fatalf("WriteYAML: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load written manifest: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(again, m) {
+		t.Errorf("round trip = %+v, want %+v", again, m)
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	for _, path := range []string{"dev.json", "team/DEV.JSON", "dev.yaml", "dev.yml", "/dev/stdin"} {
+		if err := CheckPath(path); err != nil {
+			t.Errorf("CheckPath(%q) = %v, want nil", path, err)
+		}
+	}
+	if err := CheckPath("dev.toml"); err == nil || !strings.Contains(err.Error(), "unsupported .toml file") {
+		t.Errorf("CheckPath(dev.toml) = %v, want an unsupported format error", err)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		content string
+		wantErr string
+	}{
+		{`{"repos": [`, "could not parse manifest"},
+		{`{"repos": [{"url": "not a url"}]}`, `invalid repo URL "not a url"`},
+		{`{"repos": [{"url": "git@github.com:o/r.git"}, {"url": "https://github.com/o/r"}]}`, "manifest lists github.com/o/r twice"},
+	}
+	for _, tt := range tests {
+		_, err := Load(writeManifest(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Load(%s) error = %v, want %q", tt.content, err, tt.wantErr)
+		}
+	}
+}
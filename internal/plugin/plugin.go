@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
 )
 
 const prefix = "dev-"
@@ -76,9 +78,9 @@ func DiscoverFromPATH(pathEnv string) []Plugin {
 // Run executes the plugin as a child process, inheriting stdin/stdout/stderr.
 // It sets DEV_ROOT and DEV_CWD environment variables.
 func Run(p Plugin, args []string) error {
-	homeDir, err := os.UserHomeDir()
+	srcRoot, err := config.SrcRoot()
 	if err != nil {
-		return fmt.Errorf("could not determine home directory: %w", err)
+		return err
 	}
 
 	cwd, err := os.Getwd()
@@ -91,7 +93,7 @@ func Run(p Plugin, args []string) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Env = append(os.Environ(),
-		"DEV_ROOT="+filepath.Join(homeDir, "src"),
+		"DEV_ROOT="+srcRoot,
 		"DEV_CWD="+cwd,
 	)
 	return cmd.Run()
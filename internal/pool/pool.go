@@ -0,0 +1,37 @@
+// Package pool runs work over many repos with bounded concurrency.
+package pool
+
+import (
+	"runtime"
+	"sync"
+)
+
+// DefaultWorkers is the default number of concurrent workers: enough to hide
+// network and disk latency without spawning a git process per repo at once.
+func DefaultWorkers() int {
+	return max(4, runtime.NumCPU())
+}
+
+// Each calls fn for every item, running at most workers calls concurrently,
+// and returns when all have finished. fn gets the item's index so results can
+// be stored in a slice without locking. A workers value below 1 means 1.
+func Each[T any](items []T, workers int, fn func(i int, item T)) {
+	workers = max(1, min(workers, len(items)))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i, items[i])
+			}
+		}()
+	}
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEach(t *testing.T) {
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	var running, peak atomic.Int32
+	results := make([]int, len(items))
+	Each(items, 3, func(i, item int) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		results[i] = item * 2
+		running.Add(-1)
+	})
+
+	for i, r := range results {
+		if r != i*2 {
+			t.Fatalf("results[%d] = %d, want %d", i, r, i*2)
+		}
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+}
+
+func TestEach_Edges(t *testing.T) {
+	Each([]string{}, 4, func(int, string) { t.Error("fn called for an empty slice") })
+
+	var calls int
+	Each([]string{"a", "b"}, 0, func(int, string) { calls++ })
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,172 @@
+// Package progress reports the progress of work over many repos. On a
+// terminal it renders a live view with a bar and per-outcome counts;
+// elsewhere it prints one line per finished item.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+)
+
+const barWidth = 30
+
+// Renderer tied to stderr, where progress is shown, so colors are detected
+// correctly when stdout is captured.
+var renderer = lipgloss.NewRenderer(os.Stderr)
+
+var (
+	titleStyle   = renderer.NewStyle().Bold(true)
+	barDoneStyle = renderer.NewStyle().Foreground(lipgloss.Color("39"))
+	barTodoStyle = renderer.NewStyle().Foreground(lipgloss.Color("238"))
+	countStyle   = renderer.NewStyle().Foreground(lipgloss.Color("252"))
+	failStyle    = renderer.NewStyle().Foreground(lipgloss.Color("203"))
+	dimStyle     = renderer.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// Tracker follows a fixed number of items through to an outcome. Its methods
+// are safe for concurrent use.
+type Tracker struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	done  int
+
+	program *tea.Program  // nil when not rendering live
+	stopped chan struct{} // closed when the live program has exited
+}
+
+// Start begins tracking total items, rendering on w. The view is live only
+// when w is a terminal. Call Stop when all items are done.
+func Start(w io.Writer, title string, total int) *Tracker {
+	t := &Tracker{w: w, total: total}
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(f.Fd()) || total == 0 {
+		return t
+	}
+
+	t.program = tea.NewProgram(newModel(title, total),
+		tea.WithOutput(w),
+		tea.WithInput(nil),
+		// Ctrl-C should interrupt the whole command, not just the view
+		tea.WithoutSignalHandler(),
+	)
+	t.stopped = make(chan struct{})
+	go func() {
+		defer close(t.stopped)
+		t.program.Run()
+	}()
+	return t
+}
+
+// Begin marks item as in progress. Only the live view shows running items.
+func (t *Tracker) Begin(item string) {
+	if t.program != nil {
+		t.program.Send(beginMsg(item))
+	}
+}
+
+// Done records item's outcome, such as "cloned" or "failed". Outcomes are
+// counted in the live view; otherwise a "[done/total] outcome item" line is
+// printed.
+func (t *Tracker) Done(item, outcome string) {
+	if t.program != nil {
+		t.program.Send(doneMsg{item: item, outcome: outcome})
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done++
+	width := len(fmt.Sprint(t.total))
+	fmt.Fprintf(t.w, "[%*d/%d] %s %s\n", width, t.done, t.total, outcome, item)
+}
+
+// Stop ends tracking, leaving the final state of the live view on screen.
+func (t *Tracker) Stop() {
+	if t.program != nil {
+		t.program.Quit()
+		<-t.stopped
+	}
+}
+
+type beginMsg string
+
+type doneMsg struct {
+	item, outcome string
+}
+
+// model is the live view: a bar, a count per outcome, and the running items.
+type model struct {
+	title   string
+	total   int
+	done    int
+	counts  map[string]int
+	order   []string // outcomes in order of first appearance
+	running map[string]bool
+}
+
+func newModel(title string, total int) model {
+	return model{title: title, total: total, counts: map[string]int{}, running: map[string]bool{}}
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case beginMsg:
+		m.running[string(msg)] = true
+	case doneMsg:
+		delete(m.running, msg.item)
+		m.done++
+		if m.counts[msg.outcome] == 0 {
+			m.order = append(m.order, msg.outcome)
+		}
+		m.counts[msg.outcome]++
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	filled := barWidth * m.done / m.total
+	bar := barDoneStyle.Render(strings.Repeat("━", filled)) +
+		barTodoStyle.Render(strings.Repeat("━", barWidth-filled))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %d/%d\n", titleStyle.Render(m.title), bar, m.done, m.total)
+
+	if len(m.order) > 0 {
+		counts := make([]string, len(m.order))
+		for i, outcome := range m.order {
+			s := countStyle
+			if outcome == "failed" {
+				s = failStyle
+			}
+			counts[i] = s.Render(fmt.Sprintf("%d %s", m.counts[outcome], outcome))
+		}
+		fmt.Fprintf(&b, "  %s\n", strings.Join(counts, "  "))
+	}
+
+	if len(m.running) > 0 {
+		names := make([]string, 0, len(m.running))
+		for name := range m.running {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		const maxShown = 3
+		line := strings.Join(names[:min(maxShown, len(names))], ", ")
+		if len(names) > maxShown {
+			line += fmt.Sprintf(" and %d more", len(names)-maxShown)
+		}
+		fmt.Fprintf(&b, "  %s\n", dimStyle.Render(line))
+	}
+	return b.String()
+}
@@ -0,0 +1,51 @@
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTracker_Lines(t *testing.T) {
+	var buf bytes.Buffer
+	tr := Start(&buf, "cloning", 10)
+	tr.Begin("github.com/me/api")
+	tr.Done("github.com/me/api", "cloned")
+	tr.Done("github.com/me/web", "failed")
+	tr.Stop()
+
+	want := "[ 1/10] cloned github.com/me/api\n[ 2/10] failed github.com/me/web\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestModel_View(t *testing.T) {
+	var m model = newModel("syncing", 8)
+	for _, item := range []string{"a", "b", "c", "d", "e"} {
+		next, _ := m.Update(beginMsg(item))
+		m = next.(model)
+	}
+	for _, msg := range []doneMsg{{"a", "fetched"}, {"b", "failed"}, {"c", "fetched"}} {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	view := m.View()
+	for _, s := range []string{"syncing", "3/8", "2 fetched", "1 failed", "d, e"} {
+		if !strings.Contains(view, s) {
+			t.Errorf("view missing %q:\n%s", s, view)
+		}
+	}
+	if strings.Index(view, "2 fetched") > strings.Index(view, "1 failed") {
+		t.Errorf("outcomes should appear in order of first occurrence:\n%s", view)
+	}
+
+	for _, item := range []string{"f", "g", "h"} {
+		next, _ := m.Update(beginMsg(item))
+		m = next.(model)
+	}
+	if view := m.View(); !strings.Contains(view, "d, e, f and 2 more") {
+		t.Errorf("view should cap running items:\n%s", view)
+	}
+}
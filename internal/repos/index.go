@@ -0,0 +1,311 @@
+package repos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/atomicfile"
+)
+
+// Index is an on-disk cache of the repos under a source root.
+//
+// Every non-repo directory visited during discovery is recorded with its
+// modification time. Adding or removing an entry in a directory bumps its
+// mtime, so on load only the directories whose mtime changed need to be
+// re-read. Repos are only checked for their .git, since removing it does not
+// touch the parent's mtime.
+type Index struct {
+	Root  string           `json:"root"`
+	Repos []string         `json:"repos"`
+	Dirs  map[string]int64 `json:"dirs"` // relative dir → mtime (unix nanoseconds)
+}
+
+// IndexPath returns the index file path (~/.cache/dev/repos.json).
+func IndexPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".cache", "dev", "repos.json"), nil
+}
+
+// DiscoverCached is like Discover but serves results from the on-disk index,
+// re-reading only the directories that changed since the index was written.
+// An unreadable index is rebuilt with a full walk, and failing to persist it
+// is ignored since the returned repos are still up to date.
+func DiscoverCached(baseDir string) []string {
+	path, err := IndexPath()
+	if err != nil {
+		repos, _ := Discover(baseDir)
+		return repos
+	}
+	repos, _ := discoverCached(baseDir, path)
+	return repos
+}
+
+// UpdateIndex refreshes the on-disk index after repos were added under baseDir
+// (e.g. by clone or new). Only changed directories are re-read.
+func UpdateIndex(baseDir string) error {
+	path, err := IndexPath()
+	if err != nil {
+		return err
+	}
+	_, err = discoverCached(baseDir, path)
+	return err
+}
+
+// Reindex discards the on-disk index and rebuilds it with a full walk of baseDir.
+func Reindex(baseDir string) ([]string, error) {
+	path, err := IndexPath()
+	if err != nil {
+		return nil, err
+	}
+	return reindex(baseDir, path)
+}
+
+func discoverCached(baseDir, indexPath string) ([]string, error) {
+	idx := loadIndex(indexPath, baseDir)
+	if idx.refresh() {
+		if err := idx.save(indexPath); err != nil {
+			return idx.sortedRepos(), err
+		}
+	}
+	return idx.sortedRepos(), nil
+}
+
+func reindex(baseDir, indexPath string) ([]string, error) {
+	idx := newIndex(baseDir)
+	idx.refresh()
+	if err := idx.save(indexPath); err != nil {
+		return nil, err
+	}
+	return idx.sortedRepos(), nil
+}
+
+func newIndex(baseDir string) *Index {
+	return &Index{Root: baseDir, Dirs: make(map[string]int64)}
+}
+
+// loadIndex reads the index at path. A missing or unreadable index, or one
+// built for a different root, yields an empty index that refresh will fill.
+func loadIndex(path, baseDir string) *Index {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newIndex(baseDir)
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Root != baseDir || idx.Dirs == nil {
+		return newIndex(baseDir)
+	}
+	return &idx
+}
+
+func (idx *Index) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("could not create index directory: %w", err)
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("could not marshal index: %w", err)
+	}
+	// Write atomically so concurrent dev invocations never read a partial file
+	if err := atomicfile.Write(path, data, 0o644); err != nil {
+		return fmt.Errorf("could not write index: %w", err)
+	}
+	return nil
+}
+
+func (idx *Index) sortedRepos() []string {
+	repos := append([]string(nil), idx.Repos...)
+	sort.Strings(repos)
+	return repos
+}
+
+// refresh brings the index up to date with the filesystem and reports whether
+// anything changed.
+func (idx *Index) refresh() bool {
+	if _, ok := idx.Dirs["."]; !ok {
+		idx.Repos = nil
+		idx.Dirs = make(map[string]int64)
+		idx.walk(".")
+		return true
+	}
+
+	var stale []string
+	for dir, mtime := range idx.Dirs {
+		if dirMtime(idx.abs(dir)) != mtime {
+			stale = append(stale, dir)
+		}
+	}
+
+	// Parents first, so pruning a parent drops its stale children too
+	sort.Strings(stale)
+	for _, dir := range stale {
+		if _, ok := idx.Dirs[dir]; !ok {
+			continue
+		}
+		idx.reconcile(dir)
+	}
+	return idx.recheckRepos() || len(stale) > 0
+}
+
+// recheckRepos drops the repos that lost their .git, which leaves the parent's
+// mtime untouched, and walks them again as plain directories. It reports
+// whether any repo was dropped.
+func (idx *Index) recheckRepos() bool {
+	var lost []string
+	for _, r := range idx.Repos {
+		if !isRepo(idx.abs(r)) {
+			lost = append(lost, r)
+		}
+	}
+	for _, r := range lost {
+		idx.prune(r)
+		if _, err := os.Stat(idx.abs(r)); err == nil {
+			idx.walk(r)
+		}
+	}
+	return len(lost) > 0
+}
+
+// reconcile re-reads a single changed directory: new children are walked,
+// vanished children are pruned, and unchanged children are left to their own
+// mtime checks.
+func (idx *Index) reconcile(dir string) {
+	if _, err := os.Stat(idx.abs(dir)); err != nil {
+		idx.prune(dir)
+		return
+	}
+
+	// The directory itself may have become a repo (e.g. git init)
+	if depth(dir) >= minRepoDepth && isRepo(idx.abs(dir)) {
+		idx.prune(dir)
+		idx.Repos = append(idx.Repos, dir)
+		return
+	}
+
+	current := make(map[string]bool)
+	for _, name := range subdirs(idx.abs(dir)) {
+		current[joinRel(dir, name)] = true
+	}
+
+	known := make(map[string]bool)
+	for d := range idx.Dirs {
+		if d != "." && parentRel(d) == dir {
+			known[d] = true
+		}
+	}
+	for _, r := range idx.Repos {
+		if parentRel(r) == dir {
+			known[r] = true
+		}
+	}
+
+	for child := range known {
+		if !current[child] {
+			idx.prune(child)
+		}
+	}
+
+	idx.Dirs[dir] = dirMtime(idx.abs(dir))
+	for child := range current {
+		if !known[child] {
+			idx.visit(child)
+		}
+	}
+}
+
+// walk records dir and discovers everything below it.
+func (idx *Index) walk(dir string) {
+	// Record mtime before reading so concurrent changes mark it stale
+	idx.Dirs[dir] = dirMtime(idx.abs(dir))
+	for _, name := range subdirs(idx.abs(dir)) {
+		idx.visit(joinRel(dir, name))
+	}
+}
+
+// visit records path as a repo if it is one, otherwise walks into it.
+func (idx *Index) visit(path string) {
+	if depth(path) >= minRepoDepth && isRepo(idx.abs(path)) {
+		idx.Repos = append(idx.Repos, path)
+		return
+	}
+	idx.walk(path)
+}
+
+// prune removes path and everything below it from the index.
+func (idx *Index) prune(path string) {
+	prefix := path + "/"
+	for d := range idx.Dirs {
+		if d == path || strings.HasPrefix(d, prefix) {
+			delete(idx.Dirs, d)
+		}
+	}
+	kept := idx.Repos[:0]
+	for _, r := range idx.Repos {
+		if r != path && !strings.HasPrefix(r, prefix) {
+			kept = append(kept, r)
+		}
+	}
+	idx.Repos = kept
+}
+
+func (idx *Index) abs(rel string) string {
+	return filepath.Join(idx.Root, filepath.FromSlash(rel))
+}
+
+// subdirs returns the names of the non-hidden subdirectories of dir.
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name()[0] == '.' {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func isRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// dirMtime returns the directory's mtime, or 0 if it cannot be stat'd.
+func dirMtime(dir string) int64 {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+func depth(rel string) int {
+	if rel == "." {
+		return 0
+	}
+	return strings.Count(rel, "/") + 1
+}
+
+func joinRel(dir, name string) string {
+	if dir == "." {
+		return name
+	}
+	return dir + "/" + name
+}
+
+func parentRel(rel string) string {
+	i := strings.LastIndex(rel, "/")
+	if i == -1 {
+		return "."
+	}
+	return rel[:i]
+}
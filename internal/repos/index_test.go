@@ -0,0 +1,211 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func mkRepo(t *testing.T, base, rel string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(base, rel, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscoverCached_BuildsIndex(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	mkRepo(t, base, "github.com/dsaiztc/dev")
+	mkRepo(t, base, "gitlab.com/group/sub/project")
+
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	want := []string{"github.com/dsaiztc/dev", "gitlab.com/group/sub/project"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverCached() = %v, want %v", got, want)
+	}
+
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("expected index file to be written: %v", err)
+	}
+}
+
+func TestDiscoverCached_ServesFromIndex(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	mkRepo(t, base, "github.com/dsaiztc/dev")
+
+	if _, err := discoverCached(base, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	// A repo added without bumping its parent's mtime is invisible to the
+	// index, so the cached entries are served without re-reading the tree.
+	org := filepath.Join(base, "github.com/dsaiztc")
+	info, err := os.Stat(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mkRepo(t, base, "github.com/dsaiztc/dotfiles")
+	if err := os.Chtimes(org, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	if want := []string{"github.com/dsaiztc/dev"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected cached result %v, got %v", want, got)
+	}
+
+	// Reindex rebuilds from scratch
+	got, err = reindex(base, indexPath)
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	if want := []string{"github.com/dsaiztc/dev", "github.com/dsaiztc/dotfiles"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reindex() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverCached_DetectsRemovedGit(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	mkRepo(t, base, "github.com/dsaiztc/dev")
+	mkRepo(t, base, "github.com/dsaiztc/dotfiles")
+
+	if _, err := discoverCached(base, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	// Removing .git only changes the repo's own mtime, not its parent's
+	if err := os.RemoveAll(filepath.Join(base, "github.com/dsaiztc/dev/.git")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	if want := []string{"github.com/dsaiztc/dotfiles"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after removing .git = %v, want %v", got, want)
+	}
+
+	// The former repo is now a plain directory, so repos created in it are found
+	mkRepo(t, base, "github.com/dsaiztc/dev/nested")
+	got, err = discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	if want := []string{"github.com/dsaiztc/dev/nested", "github.com/dsaiztc/dotfiles"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after adding a nested repo = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverCached_DetectsAddedRepos(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	mkRepo(t, base, "github.com/dsaiztc/dev")
+
+	if _, err := discoverCached(base, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	// Added by hand: in an existing org, a new org, and a new source
+	mkRepo(t, base, "github.com/dsaiztc/dotfiles")
+	mkRepo(t, base, "github.com/apache/kafka")
+	mkRepo(t, base, "gitlab.com/team/service")
+
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	want := []string{
+		"github.com/apache/kafka",
+		"github.com/dsaiztc/dev",
+		"github.com/dsaiztc/dotfiles",
+		"gitlab.com/team/service",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverCached() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverCached_DetectsRemovedRepos(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	mkRepo(t, base, "github.com/dsaiztc/dev")
+	mkRepo(t, base, "github.com/dsaiztc/dotfiles")
+	mkRepo(t, base, "gitlab.com/team/service")
+
+	if _, err := discoverCached(base, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(base, "github.com/dsaiztc/dotfiles")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(base, "gitlab.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	want := []string{"github.com/dsaiztc/dev"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverCached() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverCached_DirectoryBecomesRepo(t *testing.T) {
+	base := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	plain := filepath.Join(base, "github.com", "dsaiztc", "scratch")
+	if err := os.MkdirAll(plain, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := discoverCached(base, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	// git init in an existing directory
+	if err := os.Mkdir(filepath.Join(plain, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := discoverCached(base, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	want := []string{"github.com/dsaiztc/scratch"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverCached() = %v, want %v", got, want)
+	}
+}
+
+func TestDiscoverCached_RootChange(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "repos.json")
+	first := t.TempDir()
+	second := t.TempDir()
+	mkRepo(t, first, "github.com/dsaiztc/dev")
+	mkRepo(t, second, "gitlab.com/team/service")
+
+	if _, err := discoverCached(first, indexPath); err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+
+	got, err := discoverCached(second, indexPath)
+	if err != nil {
+		t.Fatalf("discoverCached: %v", err)
+	}
+	want := []string{"gitlab.com/team/service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("discoverCached() = %v, want %v", got, want)
+	}
+}
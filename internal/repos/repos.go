@@ -1,68 +1,69 @@
 package repos
 
 import (
-	"os"
-	"path/filepath"
+	"math"
 	"sort"
 
 	"github.com/sahilm/fuzzy"
 )
 
-// repoDepth is the expected depth of repos under baseDir: source/org/project.
-const repoDepth = 3
+// minRepoDepth is the minimum depth of repos under baseDir: source/org/project.
+// Deeper repos belong to nested orgs such as GitLab subgroups
+// (source/group/subgroup/project).
+const minRepoDepth = 3
 
-// Discover finds all repos at exactly depth 3 (source/org/project) under
-// baseDir that contain a .git directory.
+// Discover finds all repos at depth 3 or deeper (source/org[/subgroup...]/project)
+// under baseDir that contain a .git entry. Descent stops at the first repo found
+// on each branch, so repos nested inside other repos are not reported.
 func Discover(baseDir string) ([]string, error) {
-	var repos []string
-
-	err := walkToDepth(baseDir, baseDir, 0, &repos)
-	if err != nil {
-		return nil, err
-	}
-
-	sort.Strings(repos)
-	return repos, nil
+	idx := newIndex(baseDir)
+	idx.walk(".")
+	return idx.sortedRepos(), nil
 }
 
-func walkToDepth(baseDir, currentDir string, depth int, repos *[]string) error {
-	entries, err := os.ReadDir(currentDir)
-	if err != nil {
-		return nil
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() || entry.Name()[0] == '.' {
-			continue
-		}
+// frecencyWeight scales the frecency bonus added to the fuzzy match score.
+const frecencyWeight = 10
 
-		fullPath := filepath.Join(currentDir, entry.Name())
+// FuzzyMatch matches the query against repo paths and returns results sorted by
+// score. When scores is non-nil, each repo's frecency (see internal/history)
+// is blended into its fuzzy score so frequently and recently visited repos win
+// close matches.
+func FuzzyMatch(repos []string, query string, scores map[string]float64) []string {
+	matches := fuzzy.Find(query, repos)
+	ranked := make([]float64, len(matches))
+	for i, m := range matches {
+		ranked[i] = float64(m.Score) + frecencyBonus(scores[m.Str])
+	}
+	order := make([]int, len(matches))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranked[order[i]] > ranked[order[j]]
+	})
 
-		if depth+1 == repoDepth {
-			// At target depth — check for .git
-			gitDir := filepath.Join(fullPath, ".git")
-			if _, err := os.Stat(gitDir); err == nil {
-				relPath, err := filepath.Rel(baseDir, fullPath)
-				if err != nil {
-					continue
-				}
-				*repos = append(*repos, relPath)
-			}
-		} else {
-			// Not deep enough yet — keep descending
-			walkToDepth(baseDir, fullPath, depth+1, repos)
-		}
+	result := make([]string, len(matches))
+	for i, idx := range order {
+		result[i] = matches[idx].Str
 	}
+	return result
+}
 
-	return nil
+// SortByFrecency orders repos by descending frecency score, keeping the
+// existing (alphabetical) order among repos with equal scores.
+func SortByFrecency(repos []string, scores map[string]float64) []string {
+	sorted := append([]string(nil), repos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return scores[sorted[i]] > scores[sorted[j]]
+	})
+	return sorted
 }
 
-// FuzzyMatch matches the query against repo paths and returns results sorted by score.
-func FuzzyMatch(repos []string, query string) []string {
-	matches := fuzzy.Find(query, repos)
-	result := make([]string, len(matches))
-	for i, m := range matches {
-		result[i] = m.Str
+// frecencyBonus dampens a frecency score so a handful of visits nudges the
+// ranking without overriding a clearly better fuzzy match.
+func frecencyBonus(score float64) float64 {
+	if score <= 0 {
+		return 0
 	}
-	return result
+	return frecencyWeight * math.Log1p(score)
 }
@@ -47,24 +47,58 @@ func TestDiscover(t *testing.T) {
 	}
 }
 
-func TestDiscover_WrongDepth(t *testing.T) {
+func TestDiscover_TooShallow(t *testing.T) {
 	base := t.TempDir()
 
 	// Repo at depth 2 (source/project) — should not be found
 	shallow := filepath.Join(base, "github.com", "solo-project")
 	os.MkdirAll(filepath.Join(shallow, ".git"), 0o755)
 
-	// Repo at depth 4 (source/org/sub/project) — should not be found
-	deep := filepath.Join(base, "gitlab.com", "org", "sub", "project")
+	repos, err := Discover(base)
+	if err != nil {
+		t.Fatalf("Discover() error: %v", err)
+	}
+
+	if len(repos) != 0 {
+		t.Errorf("expected no repos at depth 2, got %v", repos)
+	}
+}
+
+func TestDiscover_NestedSubgroups(t *testing.T) {
+	base := t.TempDir()
+
+	// Repo at depth 4 (source/group/sub/project)
+	deep := filepath.Join(base, "gitlab.com", "group", "sub", "project")
 	os.MkdirAll(filepath.Join(deep, ".git"), 0o755)
 
+	// Repo at depth 5 (source/group/sub/subsub/project)
+	deeper := filepath.Join(base, "gitlab.com", "group", "sub", "subsub", "service")
+	os.MkdirAll(filepath.Join(deeper, ".git"), 0o755)
+
+	// Repo nested inside a depth-4 repo (should not appear)
+	os.MkdirAll(filepath.Join(deep, "third_party", "lib", ".git"), 0o755)
+
+	// Plain depth-3 repo alongside
+	flat := filepath.Join(base, "github.com", "dsaiztc", "dev")
+	os.MkdirAll(filepath.Join(flat, ".git"), 0o755)
+
 	repos, err := Discover(base)
 	if err != nil {
 		t.Fatalf("Discover() error: %v", err)
 	}
 
-	if len(repos) != 0 {
-		t.Errorf("expected no repos at wrong depth, got %v", repos)
+	want := []string{
+		"github.com/dsaiztc/dev",
+		"gitlab.com/group/sub/project",
+		"gitlab.com/group/sub/subsub/service",
+	}
+	if len(repos) != len(want) {
+		t.Fatalf("Discover() = %v, want %v", repos, want)
+	}
+	for i := range want {
+		if repos[i] != want[i] {
+			t.Errorf("Discover()[%d] = %q, want %q", i, repos[i], want[i])
+		}
 	}
 }
 
@@ -97,7 +131,7 @@ func TestFuzzyMatch(t *testing.T) {
 		"gitlab.com/team/service",
 	}
 
-	results := FuzzyMatch(repos, "kafka")
+	results := FuzzyMatch(repos, "kafka", nil)
 	if len(results) == 0 {
 		t.Fatal("FuzzyMatch('kafka') returned no results")
 	}
@@ -105,7 +139,7 @@ func TestFuzzyMatch(t *testing.T) {
 		t.Errorf("FuzzyMatch('kafka')[0] = %q, want 'github.com/apache/kafka'", results[0])
 	}
 
-	results = FuzzyMatch(repos, "dev")
+	results = FuzzyMatch(repos, "dev", nil)
 	if len(results) == 0 {
 		t.Fatal("FuzzyMatch('dev') returned no results")
 	}
@@ -113,3 +147,47 @@ func TestFuzzyMatch(t *testing.T) {
 		t.Errorf("FuzzyMatch('dev')[0] = %q, want 'github.com/dsaiztc/dev'", results[0])
 	}
 }
+
+func TestFuzzyMatch_FrecencyBreaksCloseMatches(t *testing.T) {
+	repos := []string{
+		"github.com/acme/api",
+		"github.com/acme/api-gateway",
+	}
+
+	results := FuzzyMatch(repos, "api", nil)
+	if results[0] != "github.com/acme/api" {
+		t.Fatalf("FuzzyMatch('api')[0] = %q, want 'github.com/acme/api'", results[0])
+	}
+
+	scores := map[string]float64{"github.com/acme/api-gateway": 20}
+	results = FuzzyMatch(repos, "api", scores)
+	if results[0] != "github.com/acme/api-gateway" {
+		t.Errorf("FuzzyMatch('api') with frecency [0] = %q, want 'github.com/acme/api-gateway'", results[0])
+	}
+}
+
+func TestFuzzyMatch_FrecencyDoesNotAddMatches(t *testing.T) {
+	repos := []string{"github.com/apache/kafka", "github.com/dsaiztc/dev"}
+	scores := map[string]float64{"github.com/dsaiztc/dev": 100}
+
+	results := FuzzyMatch(repos, "kafka", scores)
+	if len(results) != 1 || results[0] != "github.com/apache/kafka" {
+		t.Errorf("FuzzyMatch('kafka') = %v, want [github.com/apache/kafka]", results)
+	}
+}
+
+func TestSortByFrecency(t *testing.T) {
+	repos := []string{"a/b/one", "a/b/three", "a/b/two"}
+	scores := map[string]float64{"a/b/two": 5, "a/b/three": 1}
+
+	got := SortByFrecency(repos, scores)
+	want := []string{"a/b/two", "a/b/three", "a/b/one"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("SortByFrecency()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if repos[0] != "a/b/one" {
+		t.Error("SortByFrecency modified its input")
+	}
+}
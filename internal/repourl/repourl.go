@@ -81,3 +81,32 @@ func Parse(rawURL string) (RepoPath, error) {
 		Project: project,
 	}, nil
 }
+
+// Match reports whether a source/org/project path matches pattern. Each
+// slash-separated segment of pattern is matched with path.Match, and a "**"
+// segment matches any number of segments, so "github.com/mycompany/*" matches
+// the org's repos and "gitlab.com/group/**" also those in its subgroups.
+func Match(pattern, fullPath string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(fullPath, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(segments); i >= 0; i-- {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -92,3 +92,29 @@ func TestRepoPath_FullPath(t *testing.T) {
 		t.Errorf("FullPath() = %q, want %q", got, want)
 	}
 }
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"github.com/mycompany/*", "github.com/mycompany/api", true},
+		{"github.com/mycompany/*", "github.com/other/api", false},
+		{"github.com/mycompany/*", "github.com/mycompany/sub/api", false},
+		{"github.com/*/*", "github.com/dsaiztc/dev", true},
+		{"*/dsaiztc/*", "gitlab.com/dsaiztc/dev", true},
+		{"gitlab.com/group/**", "gitlab.com/group/sub/deep/project", true},
+		{"gitlab.com/group/**", "gitlab.com/group/project", true},
+		{"gitlab.com/group/**", "gitlab.com/other/project", false},
+		{"**", "github.com/o/r", true},
+		{"**/api-*", "github.com/o/api-users", true},
+		{"**/api-*", "github.com/o/web", false},
+		{"github.com/o/r", "github.com/o/r", true},
+		{"github.com/o", "github.com/o/r", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/repourl"
+)
+
+// ManifestFile is the optional file at a template's root that declares extra
+// variables. It is not copied into new projects.
+const ManifestFile = "dev-template.json"
+
+// Manifest describes a template's extra variables.
+type Manifest struct {
+	Variables []Variable `json:"variables"`
+}
+
+// Variable is a value the user is prompted for when the template is used.
+type Variable struct {
+	Name    string `json:"name"`              // placeholder {{name}}
+	Prompt  string `json:"prompt,omitempty"`  // defaults to Name
+	Default string `json:"default,omitempty"` // may itself contain placeholders such as {{name}}
+}
+
+// CacheDir returns where git templates are cloned (~/.cache/dev/templates).
+func CacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".cache", "dev", "templates"), nil
+}
+
+// Resolve returns the local directory of the template ref. ref is looked up
+// in cfg.Templates first; otherwise a git URL or a path is used as is, and a
+// bare name refers to a directory in cfg.GetTemplatesDir(). Git templates are
+// cloned into CacheDir, or updated there if already cloned.
+func Resolve(ref string, cfg *config.Config) (string, error) {
+	source := ref
+	if s, ok := cfg.Templates[ref]; ok {
+		source = s
+	} else if !isGitURL(ref) && !strings.ContainsRune(ref, filepath.Separator) && !strings.HasPrefix(ref, "~") {
+		source = filepath.Join(cfg.GetTemplatesDir(), ref)
+	}
+
+	if isGitURL(source) {
+		return fetch(source)
+	}
+
+	dir := config.ExpandHome(source)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("template %q not found: %w", ref, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("template %q is not a directory: %s", ref, dir)
+	}
+	return dir, nil
+}
+
+// isGitURL reports whether s is a git URL rather than a local path.
+func isGitURL(s string) bool {
+	_, err := repourl.Parse(s)
+	return err == nil
+}
+
+// fetch clones a git template into the cache, or fast-forwards an existing
+// clone. A failed update is only a warning since the cached copy still works.
+func fetch(url string) (string, error) {
+	parsed, err := repourl.Parse(url)
+	if err != nil {
+		return "", err
+	}
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheDir, parsed.FullPath())
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		cmd := exec.Command("git", "pull", "--ff-only", "--quiet")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not update template %s: %s\n", url, strings.TrimSpace(string(out)))
+		}
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", fmt.Errorf("could not create template cache: %w", err)
+	}
+	cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", url, dir)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("could not clone template %s: %w", url, err)
+	}
+	return dir, nil
+}
+
+// LoadManifest reads the template's manifest. A template without one has no
+// extra variables.
+func LoadManifest(dir string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return m, err
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("could not parse %s: %w", ManifestFile, err)
+	}
+	return m, nil
+}
+
+// Substitute replaces every {{key}} placeholder in s with its value in vars.
+// Unknown placeholders are left alone.
+func Substitute(s string, vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, "{{"+k+"}}", vars[k])
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// Render copies the template at templateDir into targetDir, substituting
+// placeholders in file and directory names and in the contents of text files.
+// The template's .git directory and manifest are skipped, and existing files
+// in targetDir are never overwritten.
+func Render(templateDir, targetDir string, vars map[string]string) error {
+	return filepath.WalkDir(templateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == ".git" {
+			return fs.SkipDir
+		}
+		if rel == ManifestFile {
+			return nil
+		}
+
+		target := filepath.Join(targetDir, Substitute(rel, vars))
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		// Files with a NUL byte are treated as binary and copied verbatim
+		if !bytes.ContainsRune(data, 0) {
+			data = []byte(Substitute(string(data), vars))
+		}
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("could not create %s: %w", target, err)
+		}
+		if _, err := f.Write(data); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// Commit stages everything in dir and makes the initial commit.
+func Commit(dir, message string) error {
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"commit", "--quiet", "-m", message},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package scaffold
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"name": "api", "module": "github.com/o/api"}
+	got := Substitute("module {{module}} // {{name}} ${{ github.sha }} {{unknown}}", vars)
+	want := "module github.com/o/api // api ${{ github.sha }} {{unknown}}"
+	if got != want {
+		t.Errorf("Substitute() = %q, want %q", got, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	tmpl := t.TempDir()
+	target := t.TempDir()
+	writeFile(t, filepath.Join(tmpl, "go.mod"), "module {{module}}\n")
+	writeFile(t, filepath.Join(tmpl, "cmd", "{{name}}", "main.go"), "package main // {{name}}\n")
+	writeFile(t, filepath.Join(tmpl, "logo.bin"), "{{name}}\x00")
+	writeFile(t, filepath.Join(tmpl, ManifestFile), `{"variables": []}`)
+	writeFile(t, filepath.Join(tmpl, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeFile(t, filepath.Join(target, "README.md"), "existing")
+	writeFile(t, filepath.Join(tmpl, "README.md"), "from template")
+
+	vars := map[string]string{"name": "api", "module": "github.com/o/api"}
+	err := Render(tmpl, target, vars)
+	if err == nil {
+		t.Fatal("expected Render to refuse overwriting README.md")
+	}
+
+	os.Remove(filepath.Join(target, "README.md"))
+	for _, p := range []string{"go.mod", "cmd", "logo.bin"} {
+		os.RemoveAll(filepath.Join(target, p))
+	}
+	if err := Render(tmpl, target, vars); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	checks := map[string]string{
+		"go.mod":          "module github.com/o/api\n",
+		"cmd/api/main.go": "package main // api\n",
+		"logo.bin":        "{{name}}\x00",
+		"README.md":       "from template",
+	}
+	for rel, want := range checks {
+		data, err := os.ReadFile(filepath.Join(target, rel))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", rel, data, err, want)
+		}
+	}
+	for _, rel := range []string{ManifestFile, ".git"} {
+		if _, err := os.Stat(filepath.Join(target, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be copied", rel)
+		}
+	}
+}
+
+func TestResolve_Local(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	templatesDir := filepath.Join(home, "templates")
+	if err := os.MkdirAll(filepath.Join(templatesDir, "go-cli"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(home, "elsewhere", "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		TemplatesDir: "~/templates",
+		Templates:    map[string]string{"web": "~/elsewhere/web"},
+	}
+
+	tests := []struct {
+		ref, want string
+	}{
+		{"go-cli", filepath.Join(templatesDir, "go-cli")},
+		{"web", filepath.Join(home, "elsewhere", "web")},
+		{filepath.Join(templatesDir, "go-cli"), filepath.Join(templatesDir, "go-cli")},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.ref, cfg)
+		if err != nil || got != tt.want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+
+	if _, err := Resolve("missing", cfg); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	m, err := LoadManifest(dir)
+	if err != nil || len(m.Variables) != 0 {
+		t.Errorf("LoadManifest(no file) = %+v, %v; want empty", m, err)
+	}
+
+	writeFile(t, filepath.Join(dir, ManifestFile), `{"variables": [{"name": "description", "prompt": "Description", "default": "The {{name}} service"}]}`)
+	m, err = LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if len(m.Variables) != 1 || m.Variables[0].Name != "description" || m.Variables[0].Default != "The {{name}} service" {
+		t.Errorf("LoadManifest() = %+v", m)
+	}
+
+	writeFile(t, filepath.Join(dir, ManifestFile), `{`)
+	if _, err := LoadManifest(dir); err == nil {
+		t.Error("expected error for invalid manifest")
+	}
+}
+
+func TestCommit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "T")
+	t.Setenv("GIT_AUTHOR_EMAIL", "t@t")
+	t.Setenv("GIT_COMMITTER_NAME", "T")
+	t.Setenv("GIT_COMMITTER_EMAIL", "t@t")
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "main.go"), "package main\n")
+
+	if err := Commit(dir, "Initial commit"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	if err != nil || string(out) != "Initial commit\n" {
+		t.Errorf("git log = %q, %v", out, err)
+	}
+}
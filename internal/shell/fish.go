@@ -0,0 +1,53 @@
+package shell
+
+func init() {
+	Register("fish", FishWrapperFunc)
+}
+
+// FishWrapperFunc returns the fish equivalent of WrapperFunc, with the same
+// contract: directives printed on stdout by cd, clone, new, wkt cd/new/rm and
+// grep --interactive are applied in the calling shell and any other output is ignored.
+func FishWrapperFunc() string {
+	return `function __dev_wrapper_unquote
+  string replace -r "^'(.*)'\$" '$1' -- $argv[1] | string replace -a "'\\''" "'"
+end
+
+function __dev_wrapper_apply
+  for line in $argv
+    switch $line
+      case '` + DirectiveCD + ` *'
+        cd (__dev_wrapper_unquote (string replace '` + DirectiveCD + ` ' '' -- $line))
+      case '` + DirectiveSetenv + ` *'
+        set -l rest (string split -m 1 ' ' -- (string replace '` + DirectiveSetenv + ` ' '' -- $line))
+        set -gx $rest[1] (__dev_wrapper_unquote $rest[2])
+      case '` + DirectiveSource + ` *'
+        source (__dev_wrapper_unquote (string replace '` + DirectiveSource + ` ' '' -- $line))
+    end
+  end
+end
+
+function dev
+  set -l wrap 0
+  switch "$argv[1]"
+    case cd clone new
+      set wrap 1
+    case wkt
+      if contains -- "$argv[2]" cd new rm
+        set wrap 1
+      end
+    case grep
+      if contains -- --interactive $argv
+        set wrap 1
+      end
+  end
+  if test $wrap -eq 1
+    set -l output (command dev $argv)
+    set -l exit_code $status
+    if test $exit_code -eq 0
+      __dev_wrapper_apply $output
+    end
+    return $exit_code
+  end
+  command dev $argv
+end`
+}
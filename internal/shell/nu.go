@@ -0,0 +1,38 @@
+package shell
+
+func init() {
+	Register("nu", NuWrapperFunc)
+}
+
+// NuWrapperFunc returns the Nushell wrapper command. It is declared with
+// --env so its cd and environment changes apply to the caller. Nushell can
+// only source files known at parse time, so source directives are reported
+// instead of applied.
+func NuWrapperFunc() string {
+	return `def __dev_wrapper_unquote [] {
+  $in | str replace -r "^'(.*)'$" '$1' | str replace -a "'\\''" "'"
+}
+
+def --env --wrapped dev [...args: string] {
+  let wrap = (($args | length) > 0 and (
+    ($args.0 in ["cd" "clone" "new"]) or
+    ($args.0 == "wkt" and ($args | length) > 1 and ($args.1 in ["cd" "new" "rm"])) or
+    ($args.0 == "grep" and ("--interactive" in $args))
+  ))
+  if not $wrap {
+    ^dev ...$args
+    return
+  }
+  let output = (^dev ...$args)
+  for line in ($output | lines) {
+    if ($line | str starts-with "` + DirectiveCD + ` ") {
+      cd ($line | str replace "` + DirectiveCD + ` " "" | __dev_wrapper_unquote)
+    } else if ($line | str starts-with "` + DirectiveSetenv + ` ") {
+      let rest = ($line | str replace "` + DirectiveSetenv + ` " "" | split row -n 2 " ")
+      load-env {($rest.0): ($rest.1 | __dev_wrapper_unquote)}
+    } else if ($line | str starts-with "` + DirectiveSource + ` ") {
+      print -e $"dev: cannot source ($line | str replace '` + DirectiveSource + ` ' '' | __dev_wrapper_unquote) from nushell"
+    }
+  }
+}`
+}
@@ -0,0 +1,46 @@
+package shell
+
+import "strconv"
+
+func init() {
+	Register("pwsh", PwshWrapperFunc)
+}
+
+// PwshWrapperFunc returns the PowerShell wrapper function. It applies the
+// directives printed on stdout by cd, clone, new, wkt cd/new/rm and grep
+// --interactive natively (Set-Location, $env:, dot-sourcing) and ignores any
+// other output.
+func PwshWrapperFunc() string {
+	return `function __dev_wrapper_unquote([string]$s) {
+  ($s -replace '^''(.*)''$', '$1').Replace("'\''", "'")
+}
+
+function dev {
+  $devBin = Get-Command -Name dev -CommandType Application | Select-Object -First 1
+  $wrap = $args.Count -gt 0 -and (
+    @('cd', 'clone', 'new') -contains $args[0] -or
+    ($args[0] -eq 'wkt' -and $args.Count -gt 1 -and @('cd', 'new', 'rm') -contains $args[1]) -or
+    ($args[0] -eq 'grep' -and $args -contains '--interactive')
+  )
+  if (-not $wrap) {
+    & $devBin @args
+    return
+  }
+  $output = & $devBin @args
+  $exitCode = $LASTEXITCODE
+  if ($exitCode -eq 0) {
+    foreach ($line in @($output)) {
+      if (-not $line) { continue }
+      if ($line.StartsWith('` + DirectiveCD + ` ')) {
+        Set-Location -LiteralPath (__dev_wrapper_unquote $line.Substring(` + strconv.Itoa(len(DirectiveCD)+1) + `))
+      } elseif ($line.StartsWith('` + DirectiveSetenv + ` ')) {
+        $name, $value = $line.Substring(` + strconv.Itoa(len(DirectiveSetenv)+1) + `).Split(' ', 2)
+        Set-Item -LiteralPath "Env:$name" -Value (__dev_wrapper_unquote $value)
+      } elseif ($line.StartsWith('` + DirectiveSource + ` ')) {
+        . (__dev_wrapper_unquote $line.Substring(` + strconv.Itoa(len(DirectiveSource)+1) + `))
+      }
+    }
+  }
+  $global:LASTEXITCODE = $exitCode
+}`
+}
@@ -1,16 +1,118 @@
 package shell
 
-// WrapperFunc returns the shell function that wraps the dev binary.
-// The function evals stdout from cd and clone commands so they can
-// affect the parent shell (e.g., change directory).
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directives are the only lines of stdout a wrapper acts on. Each is written
+// on its own line as "<directive> <quoted value>" (SETENV has an unquoted
+// variable name before the value); everything else is ignored.
+const (
+	DirectiveCD     = "__DEV_CD__"     // change to the quoted directory
+	DirectiveSetenv = "__DEV_SETENV__" // export NAME to the quoted value
+	DirectiveSource = "__DEV_SOURCE__" // source the quoted file
+)
+
+// Quote single-quotes s POSIX-style, escaping each embedded quote:
+//
+//	it's → 'it'\''s'
+//
+// Wrappers undo this by stripping the outer quotes and turning each escaped
+// quote back into a plain one, without evaluating anything. s must not contain
+// a newline since directives are line-based.
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Generator returns the source of a shell's wrapper function.
+//
+// Every wrapper honors the same contract: for cd, clone, new, wkt cd/new/rm
+// and grep --interactive it captures the stdout of the dev binary and applies
+// the directives in it natively; all other commands (including the __complete requests made by
+// dev completion scripts) run unwrapped. Helper functions are prefixed
+// __dev_wrapper_ so they never collide with the __dev_* functions those
+// completion scripts define.
+type Generator func() string
+
+var generators = make(map[string]Generator)
+
+// Register adds a wrapper generator under the given shell name.
+func Register(name string, g Generator) {
+	generators[name] = g
+}
+
+// Supported returns the names of the shells dev init can generate a wrapper for.
+func Supported() []string {
+	names := make([]string, 0, len(generators))
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect returns the shell name for a $SHELL value (e.g. "/usr/bin/fish" → "fish").
+// Unknown or empty values fall back to "bash", whose wrapper also works in zsh.
+func Detect(shellPath string) string {
+	name := filepath.Base(shellPath)
+	if _, ok := generators[name]; ok {
+		return name
+	}
+	return "bash"
+}
+
+// Wrapper returns the wrapper function for the named shell.
+func Wrapper(name string) (string, error) {
+	g, ok := generators[name]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", name, strings.Join(Supported(), ", "))
+	}
+	return g(), nil
+}
+
+func init() {
+	Register("bash", WrapperFunc)
+	Register("zsh", WrapperFunc)
+}
+
+// WrapperFunc returns the bash/zsh function that wraps the dev binary.
+// The function applies the directives printed on stdout by cd, clone, new,
+// wkt cd/new/rm and grep --interactive so they can affect the parent shell
+// (e.g., change directory).
+// Any other output is ignored.
 func WrapperFunc() string {
-	return `dev() {
-  if [[ "$1" == "cd" || "$1" == "clone" || "$1" == "new" || ( "$1" == "wkt" && "$2" =~ ^(cd|new|rm)$ ) ]]; then
+	return `__dev_wrapper_unquote() {
+  local q="'" esc="'\\''"
+  local s="${1#$q}"
+  s="${s%$q}"
+  printf '%s' "${s//"$esc"/$q}"
+}
+
+__dev_wrapper_apply() {
+  local line rest
+  while IFS= read -r line; do
+    case "$line" in
+      "` + DirectiveCD + ` "*)
+        builtin cd -- "$(__dev_wrapper_unquote "${line#` + DirectiveCD + ` }")" ;;
+      "` + DirectiveSetenv + ` "*)
+        rest="${line#` + DirectiveSetenv + ` }"
+        export "${rest%% *}=$(__dev_wrapper_unquote "${rest#* }")" ;;
+      "` + DirectiveSource + ` "*)
+        builtin source "$(__dev_wrapper_unquote "${line#` + DirectiveSource + ` }")" ;;
+    esac
+  done <<< "$1"
+}
+
+dev() {
+  if [[ "$1" == "cd" || "$1" == "clone" || "$1" == "new" || ( "$1" == "wkt" && "$2" =~ ^(cd|new|rm)$ ) || ( "$1" == "grep" && " $* " == *" --interactive "* ) ]]; then
     local output
     output="$(command dev "$@")"
     local exit_code=$?
     if [[ $exit_code -eq 0 && -n "$output" ]]; then
-      eval "$output"
+      __dev_wrapper_apply "$output"
     fi
     return $exit_code
   else
@@ -0,0 +1,183 @@
+package shell
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		shellPath string
+		want      string
+	}{
+		{"/bin/bash", "bash"},
+		{"/bin/zsh", "zsh"},
+		{"/usr/local/bin/fish", "fish"},
+		{"/usr/bin/pwsh", "pwsh"},
+		{"/opt/homebrew/bin/nu", "nu"},
+		{"/bin/tcsh", "bash"},
+		{"", "bash"},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.shellPath); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.shellPath, got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	got := strings.Join(Supported(), ",")
+	if got != "bash,fish,nu,pwsh,zsh" {
+		t.Errorf("Supported() = %s", got)
+	}
+}
+
+func TestWrapper(t *testing.T) {
+	for _, name := range []string{"bash", "zsh"} {
+		got, err := Wrapper(name)
+		if err != nil {
+			t.Fatalf("Wrapper(%q): %v", name, err)
+		}
+		if got != WrapperFunc() {
+			t.Errorf("Wrapper(%q) should return the bash/zsh wrapper", name)
+		}
+	}
+
+	fish, err := Wrapper("fish")
+	if err != nil {
+		t.Fatalf("Wrapper(fish): %v", err)
+	}
+	if !strings.Contains(fish, "\nfunction dev\n") {
+		t.Errorf("fish wrapper should define a fish function, got:\n%s", fish)
+	}
+	for _, sub := range []string{"case cd clone new", "contains -- \"$argv[2]\" cd new rm", "__dev_wrapper_apply $output"} {
+		if !strings.Contains(fish, sub) {
+			t.Errorf("fish wrapper missing %q", sub)
+		}
+	}
+
+	if _, err := Wrapper("tcsh"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+// TestWrapper_Golden compares each shell's wrapper against testdata/<shell>.golden.
+// Run with -update to regenerate the golden files after an intended change.
+func TestWrapper_Golden(t *testing.T) {
+	for _, name := range Supported() {
+		t.Run(name, func(t *testing.T) {
+			got, err := Wrapper(name)
+			if err != nil {
+				t.Fatalf("Wrapper(%q): %v", name, err)
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("could not read golden file (run with -update to create it): %v", err)
+			}
+			if got+"\n" != string(want) {
+				t.Errorf("wrapper for %s does not match %s:\n%s", name, golden, got)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/home/u/src/github.com/acme/api", `'/home/u/src/github.com/acme/api'`},
+		{"/tmp/with space", `'/tmp/with space'`},
+		{"/tmp/it's", `'/tmp/it'\''s'`},
+		{"", `''`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestWrapperFunc_Bash runs the bash wrapper against a fake dev binary and
+// checks that directives are applied and everything else is ignored.
+func TestWrapperFunc_Bash(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	target := filepath.Join(t.TempDir(), "it's a $(dir) `with` spaces")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sourced := filepath.Join(t.TempDir(), "env.sh")
+	if err := os.WriteFile(sourced, []byte("SOURCED=yes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fake dev binary that prints noise around the directives
+	binDir := t.TempDir()
+	stdout := "echo pwned; touch /tmp/should-not-run\n" +
+		DirectiveCD + " " + Quote(target) + "\n" +
+		DirectiveSetenv + " DEV_TEST " + Quote("a b'c $HOME") + "\n" +
+		DirectiveSource + " " + Quote(sourced) + "\n"
+	script := "#!/bin/sh\ncat <<'EOF'\n" + stdout + "EOF\n"
+	if err := os.WriteFile(filepath.Join(binDir, "dev"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bash, "-c", WrapperFunc()+"\n"+`dev cd && printf '%s\n%s\n%s\n' "$PWD" "$DEV_TEST" "$SOURCED"`)
+	cmd.Env = append(os.Environ(), "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash: %v\n%s", err, out)
+	}
+
+	want := target + "\n" + "a b'c $HOME\n" + "yes\n"
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+// TestWrapperFunc_BashGrep checks that dev grep output passes through the
+// bash wrapper untouched unless --interactive asks for a cd.
+func TestWrapperFunc_BashGrep(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	target := t.TempDir()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho " + Quote(DirectiveCD+" "+Quote(target)) + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "dev"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bash, "-c", WrapperFunc()+"\n"+`dev grep foo && dev grep --interactive foo && echo "$PWD"`)
+	cmd.Env = append(os.Environ(), "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash: %v\n%s", err, out)
+	}
+
+	want := DirectiveCD + " " + Quote(target) + "\n" + target + "\n"
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
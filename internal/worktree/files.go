@@ -0,0 +1,131 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+// CarryFiles copies or symlinks the paths matching files' glob patterns from
+// the main worktree at mainPath into the worktree at targetPath. Patterns are
+// matched relative to mainPath with path.Match syntax (no "**"). Paths that
+// already exist in the target, such as tracked files, are left alone, and
+// anything under .git is never touched. Every path is attempted; the errors
+// of those that failed are joined.
+func CarryFiles(mainPath, targetPath string, files config.WorktreeFiles) error {
+	var errs []string
+	carry := func(patterns []string, link bool) {
+		for _, rel := range matchFiles(mainPath, patterns) {
+			dst := filepath.Join(targetPath, rel)
+			if _, err := os.Lstat(dst); err == nil {
+				continue
+			}
+			if err := carryFile(filepath.Join(mainPath, rel), dst, link); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", rel, err))
+			}
+		}
+	}
+	carry(files.Symlink, true)
+	carry(files.Copy, false)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("could not carry files into worktree: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// matchFiles returns the slash-separated paths under root matching any of
+// patterns, without duplicates and excluding .git.
+func matchFiles(root string, patterns []string) []string {
+	fsys := os.DirFS(root)
+	seen := make(map[string]bool)
+	var matches []string
+	for _, pattern := range patterns {
+		found, err := fs.Glob(fsys, strings.TrimPrefix(filepath.ToSlash(pattern), "./"))
+		if err != nil {
+			continue
+		}
+		for _, rel := range found {
+			if rel == ".git" || strings.HasPrefix(rel, ".git/") || seen[rel] {
+				continue
+			}
+			seen[rel] = true
+			matches = append(matches, rel)
+		}
+	}
+	return matches
+}
+
+// carryFile symlinks or copies src to dst, creating dst's parent directories.
+func carryFile(src, dst string, link bool) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if link {
+		return os.Symlink(src, dst)
+	}
+	return copyTree(src, dst)
+}
+
+// copyTree copies a file, symlink or directory tree from src to dst,
+// preserving permissions and cloning regular files where possible.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			linkTarget, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(linkTarget, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		// Sockets, pipes and devices are skipped
+		return nil
+	})
+}
+
+// copyFile copies a regular file, as a copy-on-write reflink when the
+// filesystem supports it and byte by byte otherwise.
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := cloneFile(src, dst, perm); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,100 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCarryFiles(t *testing.T) {
+	mainPath := t.TempDir()
+	target := t.TempDir()
+
+	writeFile(t, filepath.Join(mainPath, ".env"), "SECRET=1\n", 0o600)
+	writeFile(t, filepath.Join(mainPath, "node_modules", "pkg", "index.js"), "x", 0o644)
+	writeFile(t, filepath.Join(mainPath, "config", "app.local.json"), "{}", 0o644)
+	writeFile(t, filepath.Join(mainPath, "README.md"), "main copy", 0o644)
+	writeFile(t, filepath.Join(target, "README.md"), "tracked", 0o644)
+	writeFile(t, filepath.Join(mainPath, ".git", "config"), "", 0o644)
+	if err := os.Symlink("pkg", filepath.Join(mainPath, "node_modules", "alias")); err != nil {
+		t.Fatal(err)
+	}
+
+	files := config.WorktreeFiles{
+		Copy:    []string{".env", "config/*.local.json", "README.md", ".git", "missing-*"},
+		Symlink: []string{"node_modules"},
+	}
+	if err := CarryFiles(mainPath, target, files); err != nil {
+		t.Fatalf("CarryFiles: %v", err)
+	}
+
+	info, err := os.Lstat(filepath.Join(target, ".env"))
+	if err != nil || !info.Mode().IsRegular() || info.Mode().Perm() != 0o600 {
+		t.Errorf(".env = %v, %v; want a regular 0600 copy", info, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "config", "app.local.json")); string(data) != "{}" {
+		t.Errorf("config/app.local.json = %q, want copy", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(target, "README.md")); string(data) != "tracked" {
+		t.Errorf("README.md = %q, existing files must not be overwritten", data)
+	}
+	if link, err := os.Readlink(filepath.Join(target, "node_modules")); err != nil || link != filepath.Join(mainPath, "node_modules") {
+		t.Errorf("node_modules link = %q, %v; want symlink to main worktree", link, err)
+	}
+	if _, err := os.Lstat(filepath.Join(target, ".git")); !os.IsNotExist(err) {
+		t.Errorf(".git must never be carried over")
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	dst := filepath.Join(t.TempDir(), "dst")
+	writeFile(t, filepath.Join(src, "bin", "run"), "#!/bin/sh\n", 0o755)
+	if err := os.Symlink("bin/run", filepath.Join(src, "run")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyTree(src, dst); err != nil {
+		t.Fatalf("copyTree: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dst, "bin", "run"))
+	if err != nil || info.Mode().Perm() != 0o755 {
+		t.Errorf("bin/run = %v, %v; want executable copy", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "run")); err != nil || link != "bin/run" {
+		t.Errorf("run link = %q, %v; want relative symlink preserved", link, err)
+	}
+}
+
+func TestCreateWorktree_CarriesFiles(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	writeFile(t, filepath.Join(info.MainPath, ".env"), "A=1\n", 0o644)
+	cfg := &config.Config{
+		Repos: map[string]config.RepoConfig{
+			"github.com/testuser/clone": {WorktreeFiles: config.WorktreeFiles{Copy: []string{".env"}}},
+		},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+
+	path, err := CreateWorktree(info, "with-env", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(path, ".env")); err != nil || string(data) != "A=1\n" {
+		t.Errorf(".env in new worktree = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,129 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Reasons a linked worktree is considered stale.
+const (
+	ReasonMerged       = "merged"        // branch is merged into the default branch
+	ReasonUpstreamGone = "upstream gone" // branch tracked a remote branch that was deleted
+	ReasonMissing      = "missing"       // worktree directory no longer exists
+)
+
+// PruneCandidate is a linked worktree that looks safe to clean up.
+type PruneCandidate struct {
+	Repo     *RepoInfo
+	Worktree Worktree
+	Reasons  []string
+	Risks    Risks // work RemoveWorktree would refuse to lose without Force
+}
+
+// branchInfo is the tip and upstream state of a local branch.
+type branchInfo struct {
+	tip          string
+	upstreamGone bool
+}
+
+// FindPruneCandidates returns the linked worktrees of the repo whose branch
+// is merged into the default branch, whose upstream is gone, or whose
+// directory no longer exists. Branches still pointing at the default branch
+// tip (e.g. just created) are not reported as merged.
+func FindPruneCandidates(repoInfo *RepoInfo) ([]PruneCandidate, error) {
+	worktrees, err := ListWorktrees(repoInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	refCmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)%09%(objectname)%09%(upstream:track)", "refs/heads")
+	refCmd.Dir = repoInfo.MainPath
+	refOut, err := refCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not list branches: %w", err)
+	}
+	branches := parseBranchInfo(string(refOut))
+
+	merged := make(map[string]bool)
+	var defaultTip string
+	if defaultBranch := DefaultBranch(repoInfo); defaultBranch != "" {
+		tipCmd := exec.Command("git", "rev-parse", "--verify", "--quiet", defaultBranch+"^{commit}")
+		tipCmd.Dir = repoInfo.MainPath
+		if out, err := tipCmd.Output(); err == nil {
+			defaultTip = strings.TrimSpace(string(out))
+		}
+
+		mergedCmd := exec.Command("git", "for-each-ref", "--merged="+defaultBranch, "--format=%(refname:short)", "refs/heads")
+		mergedCmd.Dir = repoInfo.MainPath
+		out, err := mergedCmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("could not list merged branches: %w", err)
+		}
+		for _, name := range strings.Fields(string(out)) {
+			merged[name] = true
+		}
+	}
+
+	var candidates []PruneCandidate
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			continue
+		}
+
+		var reasons []string
+		if _, err := os.Stat(wt.Path); err != nil {
+			reasons = append(reasons, ReasonMissing)
+		}
+		if info, ok := branches[wt.Branch]; ok {
+			if merged[wt.Branch] && info.tip != defaultTip {
+				reasons = append(reasons, ReasonMerged)
+			}
+			if info.upstreamGone {
+				reasons = append(reasons, ReasonUpstreamGone)
+			}
+		}
+		if len(reasons) == 0 {
+			continue
+		}
+
+		risks, err := CheckRemoval(repoInfo, wt)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, PruneCandidate{Repo: repoInfo, Worktree: wt, Reasons: reasons, Risks: risks})
+	}
+	return candidates, nil
+}
+
+// parseBranchInfo parses `git for-each-ref --format=%(refname:short)%09%(objectname)%09%(upstream:track)`
+// output, where the track column is "[gone]" for a deleted upstream.
+func parseBranchInfo(output string) map[string]branchInfo {
+	branches := make(map[string]branchInfo)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		branches[fields[0]] = branchInfo{tip: fields[1], upstreamGone: fields[2] == "[gone]"}
+	}
+	return branches
+}
+
+// DefaultBranch returns the ref other branches are merged into: origin's
+// HEAD (e.g. "origin/main") when known, otherwise the branch checked out in
+// the main worktree. Returns "" if neither can be determined.
+func DefaultBranch(repoInfo *RepoInfo) string {
+	for _, args := range [][]string{
+		{"symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"},
+		{"symbolic-ref", "--quiet", "--short", "HEAD"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoInfo.MainPath
+		if out, err := cmd.Output(); err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseBranchInfo(t *testing.T) {
+	output := "main\taaa\t\n" +
+		"feature\tbbb\t[ahead 1]\n" +
+		"old\tccc\t[gone]\n"
+
+	got := parseBranchInfo(output)
+	want := map[string]branchInfo{
+		"main":    {tip: "aaa"},
+		"feature": {tip: "bbb"},
+		"old":     {tip: "ccc", upstreamGone: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseBranchInfo() = %v, want %v", got, want)
+	}
+}
+
+func TestFindPruneCandidates(t *testing.T) {
+	homeDir, upstream := setupTestRepo(t)
+	t.Setenv("HOME", homeDir)
+	t.Setenv("DEV_SRC_ROOT", "")
+	identity := []string{"-c", "user.email=t@t", "-c", "user.name=T"}
+
+	// Upstream: feat-gone at the initial commit, feat-merged merged into the
+	// default branch, which then moves one commit further.
+	gitOutput(t, upstream, "branch", "feat-gone")
+	gitOutput(t, upstream, "checkout", "-q", "-b", "feat-merged")
+	gitOutput(t, upstream, append(identity, "commit", "-q", "--allow-empty", "-m", "feature")...)
+	gitOutput(t, upstream, "checkout", "-q", "-")
+	gitOutput(t, upstream, "merge", "-q", "--ff-only", "feat-merged")
+	gitOutput(t, upstream, append(identity, "commit", "-q", "--allow-empty", "-m", "later")...)
+
+	clonePath := filepath.Join(homeDir, "src", "github.com", "testuser", "clone")
+	gitOutput(t, homeDir, "clone", "-q", upstream, clonePath)
+	info := &RepoInfo{MainPath: clonePath, Source: "github.com", Org: "testuser", Repo: "clone"}
+
+	for _, b := range []string{"feat-merged", "feat-gone", "fresh", "doomed"} {
+		if _, err := CreateWorktree(info, b, CreateOptions{}); err != nil {
+			t.Fatalf("CreateWorktree(%s): %v", b, err)
+		}
+	}
+	gitOutput(t, upstream, "branch", "-q", "-D", "feat-gone")
+	gitOutput(t, clonePath, "fetch", "-q", "--prune")
+
+	doomed := FormatWorktreePath(filepath.Join(homeDir, "src__worktrees"), "github.com", "testuser", "clone", "doomed")
+	if err := os.RemoveAll(doomed); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := FindPruneCandidates(info)
+	if err != nil {
+		t.Fatalf("FindPruneCandidates: %v", err)
+	}
+
+	got := make(map[string][]string)
+	for _, c := range candidates {
+		got[c.Worktree.Branch] = c.Reasons
+		if !c.Risks.Empty() {
+			t.Errorf("%s: unexpected risks %+v", c.Worktree.Branch, c.Risks)
+		}
+	}
+	want := map[string][]string{
+		"doomed":      {ReasonMissing},
+		"feat-gone":   {ReasonMerged, ReasonUpstreamGone},
+		"feat-merged": {ReasonMerged},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindPruneCandidates() reasons = %v, want %v", got, want)
+	}
+
+	// Candidates are removed through RemoveWorktree, missing directories included
+	for _, c := range candidates {
+		if _, err := RemoveWorktree(c.Repo, c.Worktree, RemoveOptions{}); err != nil {
+			t.Errorf("RemoveWorktree(%s): %v", c.Worktree.Branch, err)
+		}
+	}
+	worktrees, err := ListWorktrees(info)
+	if err != nil {
+		t.Fatalf("ListWorktrees: %v", err)
+	}
+	if len(worktrees) != 2 || worktrees[1].Branch != "fresh" {
+		t.Errorf("remaining worktrees = %+v, want main and fresh", worktrees)
+	}
+}
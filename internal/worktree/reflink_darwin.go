@@ -0,0 +1,13 @@
+package worktree
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile creates dst as an APFS clone of src with clonefile(2), which
+// keeps src's permissions. On failure dst does not exist.
+func cloneFile(src, dst string, perm fs.FileMode) error {
+	return unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW)
+}
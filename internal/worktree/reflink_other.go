@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package worktree
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// cloneFile is not supported on this platform; callers fall back to copying.
+func cloneFile(src, dst string, perm fs.FileMode) error {
+	return errors.ErrUnsupported
+}
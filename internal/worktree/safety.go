@@ -0,0 +1,96 @@
+package worktree
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// RemoveOptions controls how RemoveWorktree treats work that would be lost.
+type RemoveOptions struct {
+	Force        bool // remove even if CheckRemoval reports risks
+	DeleteRemote bool // also delete the branch on origin
+}
+
+// Risks lists the work that removing a worktree and its branch would destroy
+// or orphan.
+type Risks struct {
+	Changed   int      // tracked files with uncommitted changes
+	Untracked int      // untracked files
+	Stashes   []string // stash entries created on the branch (e.g. "stash@{0}")
+	Unpushed  int      // commits on the branch not present on any remote
+}
+
+// Empty reports whether nothing would be lost.
+func (r Risks) Empty() bool {
+	return r.Changed == 0 && r.Untracked == 0 && len(r.Stashes) == 0 && r.Unpushed == 0
+}
+
+// Summary returns one human-readable line per risk.
+func (r Risks) Summary() []string {
+	var lines []string
+	if r.Changed > 0 {
+		lines = append(lines, fmt.Sprintf("%d file(s) with uncommitted changes", r.Changed))
+	}
+	if r.Untracked > 0 {
+		lines = append(lines, fmt.Sprintf("%d untracked file(s)", r.Untracked))
+	}
+	if len(r.Stashes) > 0 {
+		lines = append(lines, fmt.Sprintf("%d stash(es) made on this branch: %s", len(r.Stashes), strings.Join(r.Stashes, ", ")))
+	}
+	if r.Unpushed > 0 {
+		lines = append(lines, fmt.Sprintf("%d commit(s) not on any remote", r.Unpushed))
+	}
+	return lines
+}
+
+// CheckRemoval inspects a linked worktree and its branch for work that
+// RemoveWorktree would lose. A worktree whose directory is already gone only
+// has its stashes and commits checked.
+func CheckRemoval(repoInfo *RepoInfo, wt Worktree) (Risks, error) {
+	var risks Risks
+
+	st := GetStatus(wt)
+	risks.Changed, risks.Untracked = st.Changed, st.Untracked
+
+	if wt.Branch == "" {
+		return risks, nil
+	}
+
+	stashCmd := exec.Command("git", "stash", "list", "--format=%gd%x09%gs")
+	stashCmd.Dir = repoInfo.MainPath
+	stashes, err := stashCmd.Output()
+	if err != nil {
+		return risks, fmt.Errorf("could not list stashes: %w", err)
+	}
+	risks.Stashes = ParseStashList(string(stashes), wt.Branch)
+
+	// Commits reachable from the branch but from no remote-tracking ref
+	countCmd := exec.Command("git", "rev-list", "--count", "refs/heads/"+wt.Branch, "--not", "--remotes")
+	countCmd.Dir = repoInfo.MainPath
+	count, err := countCmd.Output()
+	if err != nil {
+		return risks, fmt.Errorf("could not count unpushed commits: %w", err)
+	}
+	risks.Unpushed, _ = strconv.Atoi(strings.TrimSpace(string(count)))
+
+	return risks, nil
+}
+
+// ParseStashList returns the stash refs from `git stash list
+// --format=%gd%x09%gs` output whose subject shows they were made on branch.
+// Subjects look like "WIP on <branch>: ..." or "On <branch>: <message>".
+func ParseStashList(output, branch string) []string {
+	var refs []string
+	for _, line := range strings.Split(output, "\n") {
+		ref, subject, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(subject, "WIP on "+branch+":") || strings.HasPrefix(subject, "On "+branch+":") {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
@@ -0,0 +1,114 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseStashList(t *testing.T) {
+	output := "stash@{0}\tWIP on feature: 1234567 add thing\n" +
+		"stash@{1}\tOn main: experiment\n" +
+		"stash@{2}\tOn feature: saved for later\n" +
+		"stash@{3}\tWIP on feature-2: 89abcde other\n"
+
+	got := ParseStashList(output, "feature")
+	want := []string{"stash@{0}", "stash@{2}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStashList() = %v, want %v", got, want)
+	}
+}
+
+func TestRisksSummary(t *testing.T) {
+	if !(Risks{}).Empty() {
+		t.Error("zero Risks should be empty")
+	}
+	r := Risks{Changed: 1, Untracked: 2, Stashes: []string{"stash@{0}"}, Unpushed: 3}
+	if r.Empty() {
+		t.Error("Risks with changes should not be empty")
+	}
+	if got := len(r.Summary()); got != 4 {
+		t.Errorf("Summary() has %d lines, want 4", got)
+	}
+}
+
+func TestCheckRemoval_Clean(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	path, err := CreateWorktree(info, "clean", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+
+	risks, err := CheckRemoval(info, Worktree{Path: path, Branch: "clean"})
+	if err != nil {
+		t.Fatalf("CheckRemoval: %v", err)
+	}
+	if !risks.Empty() {
+		t.Errorf("CheckRemoval() = %+v, want no risks", risks)
+	}
+
+	if _, err := RemoveWorktree(info, Worktree{Path: path, Branch: "clean"}, RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("worktree dir still exists after removal")
+	}
+	if hasRef(info, "refs/heads/clean") {
+		t.Error("local branch still exists after removal")
+	}
+}
+
+func TestCheckRemoval_Risky(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	path, err := CreateWorktree(info, "risky", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	identity := []string{"-c", "user.email=t@t", "-c", "user.name=T"}
+
+	// One unpushed commit
+	if err := os.WriteFile(filepath.Join(path, "committed.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, path, "add", "committed.txt")
+	gitOutput(t, path, append(identity, "commit", "-qm", "local work")...)
+
+	// One stash made on the branch
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("stashed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, path, append(identity, "stash", "-q")...)
+
+	// One modified and one untracked file
+	if err := os.WriteFile(filepath.Join(path, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "scratch.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	wt := Worktree{Path: path, Branch: "risky"}
+	risks, err := CheckRemoval(info, wt)
+	if err != nil {
+		t.Fatalf("CheckRemoval: %v", err)
+	}
+	want := Risks{Changed: 1, Untracked: 1, Stashes: []string{"stash@{0}"}, Unpushed: 1}
+	if !reflect.DeepEqual(risks, want) {
+		t.Errorf("CheckRemoval() = %+v, want %+v", risks, want)
+	}
+
+	if _, err := RemoveWorktree(info, wt, RemoveOptions{}); err == nil {
+		t.Fatal("expected RemoveWorktree to refuse without Force")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("worktree dir removed despite refusal: %v", err)
+	}
+
+	if _, err := RemoveWorktree(info, wt, RemoveOptions{Force: true}); err != nil {
+		t.Fatalf("RemoveWorktree with Force: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("worktree dir still exists after forced removal")
+	}
+}
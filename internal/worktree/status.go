@@ -0,0 +1,186 @@
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Status describes the working state of a single worktree.
+type Status struct {
+	Repo       string    `json:"repo,omitempty"` // source/org/repo, set when listing across repos
+	Branch     string    `json:"branch"`
+	Path       string    `json:"path"`
+	IsMain     bool      `json:"is_main"`
+	Commit     string    `json:"commit"`
+	Missing    bool      `json:"missing,omitempty"` // directory no longer exists
+	Dirty      bool      `json:"dirty"`
+	Changed    int       `json:"changed"`   // tracked files with staged, unstaged or conflicting changes
+	Untracked  int       `json:"untracked"` // untracked files, not counting ignored ones
+	Upstream   string    `json:"upstream,omitempty"`
+	Ahead      int       `json:"ahead"`
+	Behind     int       `json:"behind"`
+	LastCommit time.Time `json:"last_commit,omitzero"`
+}
+
+// ShortCommit returns the abbreviated commit hash.
+func (s Status) ShortCommit() string {
+	if len(s.Commit) > 7 {
+		return s.Commit[:7]
+	}
+	return s.Commit
+}
+
+// GetStatus inspects a worktree with git status and git log.
+func GetStatus(wt Worktree) Status {
+	st := Status{
+		Branch: wt.Branch,
+		Path:   wt.Path,
+		IsMain: wt.IsMain,
+		Commit: wt.Commit,
+	}
+
+	if _, err := os.Stat(wt.Path); err != nil {
+		st.Missing = true
+		return st
+	}
+
+	cmd := exec.Command("git", "status", "--porcelain=v2", "--branch")
+	cmd.Dir = wt.Path
+	if out, err := cmd.Output(); err == nil {
+		ParseStatusV2(string(out), &st)
+	}
+
+	logCmd := exec.Command("git", "log", "-1", "--format=%ct")
+	logCmd.Dir = wt.Path
+	if out, err := logCmd.Output(); err == nil {
+		if ts, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64); err == nil {
+			st.LastCommit = time.Unix(ts, 0)
+		}
+	}
+
+	return st
+}
+
+// ParseStatusV2 fills the commit, upstream, ahead/behind and change count
+// fields of st from the output of `git status --porcelain=v2 --branch`. The
+// branch is only filled in if st doesn't have one yet.
+func ParseStatusV2(output string, st *Status) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "# branch.head "):
+			if head := strings.TrimPrefix(line, "# branch.head "); st.Branch == "" && head != "(detached)" {
+				st.Branch = head
+			}
+		case strings.HasPrefix(line, "# branch.oid "):
+			if oid := strings.TrimPrefix(line, "# branch.oid "); oid != "(initial)" {
+				st.Commit = oid
+			}
+		case strings.HasPrefix(line, "# branch.upstream "):
+			st.Upstream = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// Format: "# branch.ab +<ahead> -<behind>"
+			fields := strings.Fields(strings.TrimPrefix(line, "# branch.ab "))
+			if len(fields) == 2 {
+				st.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				st.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(line, "#"):
+			// Other headers (e.g. stash) don't affect status
+		case strings.HasPrefix(line, "? "):
+			st.Untracked++
+			st.Dirty = true
+		case line != "":
+			// Changed ("1", "2") and unmerged ("u") entries
+			st.Changed++
+			st.Dirty = true
+		}
+	}
+}
+
+// ListStatuses returns the status of every worktree of the repo.
+func ListStatuses(repoInfo *RepoInfo) ([]Status, error) {
+	worktrees, err := ListWorktrees(repoInfo)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(worktrees))
+	for i, wt := range worktrees {
+		statuses[i] = GetStatus(wt)
+	}
+	return statuses, nil
+}
+
+// DiscoverWorktreeRepos finds the repos that have linked worktrees under root
+// by reading the .git file of every worktree directory. Results are sorted by
+// main worktree path.
+func DiscoverWorktreeRepos(root, srcRoot string) []*RepoInfo {
+	seen := make(map[string]bool)
+	var infos []*RepoInfo
+
+	var walk func(dir string)
+	walk = func(dir string) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || entry.Name()[0] == '.' {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			info, err := os.Lstat(filepath.Join(path, ".git"))
+			if err != nil {
+				walk(path)
+				continue
+			}
+			if info.IsDir() {
+				// A main worktree inside the worktree root; not ours to manage
+				continue
+			}
+			mainPath, err := MainPathFromGitFile(path)
+			if err != nil || seen[mainPath] {
+				continue
+			}
+			seen[mainPath] = true
+			infos = append(infos, repoInfoForMain(mainPath, srcRoot))
+		}
+	}
+	walk(root)
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].MainPath < infos[j].MainPath
+	})
+	return infos
+}
+
+// repoInfoForMain builds a RepoInfo for a main worktree, deriving
+// source/org/repo from its location under srcRoot when possible.
+func repoInfoForMain(mainPath, srcRoot string) *RepoInfo {
+	info := &RepoInfo{MainPath: mainPath, CurrentPath: mainPath, Repo: filepath.Base(mainPath)}
+	relPath, err := filepath.Rel(srcRoot, mainPath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return info
+	}
+	if source, org, repo, err := SplitRepoPath(relPath); err == nil {
+		info.Source, info.Org, info.Repo = source, org, repo
+	}
+	return info
+}
+
+// Name returns the repo's source/org/repo path, or its directory name when
+// it lives outside the source root.
+func (r *RepoInfo) Name() string {
+	if r.Source == "" {
+		return r.Repo
+	}
+	return fmt.Sprintf("%s/%s/%s", r.Source, r.Org, r.Repo)
+}
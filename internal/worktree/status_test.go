@@ -0,0 +1,115 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseStatusV2(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Status
+	}{
+		{
+			name: "clean with upstream",
+			output: "# branch.oid 1234567890abcdef\n" +
+				"# branch.head main\n" +
+				"# branch.upstream origin/main\n" +
+				"# branch.ab +2 -3\n",
+			want: Status{Branch: "main", Commit: "1234567890abcdef", Upstream: "origin/main", Ahead: 2, Behind: 3},
+		},
+		{
+			name: "dirty without upstream",
+			output: "# branch.oid abcdef\n" +
+				"# branch.head feature\n" +
+				"1 .M N... 100644 100644 100644 aaa bbb README.md\n" +
+				"? new.txt\n",
+			want: Status{Branch: "feature", Commit: "abcdef", Dirty: true, Changed: 1, Untracked: 1},
+		},
+		{
+			name:   "untracked only",
+			output: "# branch.oid abcdef\n? new.txt\n? other.txt\n",
+			want:   Status{Commit: "abcdef", Dirty: true, Untracked: 2},
+		},
+		{
+			name:   "initial commit keeps existing commit",
+			output: "# branch.oid (initial)\n# branch.head main\n",
+			want:   Status{Branch: "main"},
+		},
+		{
+			name:   "detached head",
+			output: "# branch.oid abcdef\n# branch.head (detached)\n",
+			want:   Status{Commit: "abcdef"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Status
+			ParseStatusV2(tt.output, &got)
+			if got != tt.want {
+				t.Errorf("ParseStatusV2() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListStatuses_Integration(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	path, err := CreateWorktree(info, "feature-s", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "scratch.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := ListStatuses(info)
+	if err != nil {
+		t.Fatalf("ListStatuses: %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 statuses, got %d", len(statuses))
+	}
+
+	main, linked := statuses[0], statuses[1]
+	if !main.IsMain || main.Dirty || main.Upstream == "" || main.Ahead != 0 || main.Behind != 0 {
+		t.Errorf("main status = %+v, want clean and in sync with upstream", main)
+	}
+	if main.LastCommit.IsZero() || len(main.ShortCommit()) != 7 {
+		t.Errorf("main status = %+v, want last commit time and short commit", main)
+	}
+	if linked.Branch != "feature-s" || !linked.Dirty || linked.Upstream != "" {
+		t.Errorf("linked status = %+v, want dirty feature-s without upstream", linked)
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		t.Fatal(err)
+	}
+	if st := GetStatus(Worktree{Path: path, Branch: "feature-s"}); !st.Missing {
+		t.Errorf("GetStatus() on removed dir = %+v, want Missing", st)
+	}
+}
+
+func TestDiscoverWorktreeRepos(t *testing.T) {
+	info, wtRoot := setupClonedRepo(t)
+	for _, b := range []string{"a", "b"} {
+		if _, err := CreateWorktree(info, b, CreateOptions{}); err != nil {
+			t.Fatalf("CreateWorktree(%s): %v", b, err)
+		}
+	}
+
+	srcRoot := filepath.Join(filepath.Dir(wtRoot), "src")
+	infos := DiscoverWorktreeRepos(wtRoot, srcRoot)
+	if len(infos) != 1 {
+		t.Fatalf("expected 1 repo, got %d", len(infos))
+	}
+	if infos[0].MainPath != info.MainPath {
+		t.Errorf("MainPath = %q, want %q", infos[0].MainPath, info.MainPath)
+	}
+	if got := infos[0].Name(); got != "github.com/testuser/clone" {
+		t.Errorf("Name() = %q, want github.com/testuser/clone", got)
+	}
+}
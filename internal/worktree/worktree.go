@@ -10,13 +10,14 @@ import (
 	"strings"
 
 	"github.com/dsaiztc/dev/internal/config"
+	"github.com/dsaiztc/dev/internal/hooks"
 )
 
 // RepoInfo holds information about the current repository and its worktree context.
 type RepoInfo struct {
 	MainPath    string // absolute path to the main worktree
 	Source      string // e.g. "github.com"
-	Org         string // e.g. "dsaiztc"
+	Org         string // e.g. "dsaiztc" or "group/subgroup"
 	Repo        string // e.g. "dev"
 	CurrentPath string // absolute path to the current worktree (may equal MainPath)
 	IsLinked    bool   // true if current directory is inside a linked worktree
@@ -50,56 +51,75 @@ func DetectCurrentRepo() (*RepoInfo, error) {
 	isLinked := !info.IsDir()
 
 	if isLinked {
-		// .git is a file — parse it to find main worktree
-		data, err := os.ReadFile(gitPath)
+		mainPath, err = MainPathFromGitFile(currentPath)
 		if err != nil {
-			return nil, fmt.Errorf("could not read .git file: %w", err)
+			return nil, err
 		}
-		// Format: "gitdir: /path/to/.git/worktrees/<name>"
-		line := strings.TrimSpace(string(data))
-		if !strings.HasPrefix(line, "gitdir: ") {
-			return nil, fmt.Errorf("unexpected .git file format: %s", line)
-		}
-		gitDir := strings.TrimPrefix(line, "gitdir: ")
-		// Resolve relative paths
-		if !filepath.IsAbs(gitDir) {
-			gitDir = filepath.Join(currentPath, gitDir)
-		}
-		gitDir = filepath.Clean(gitDir)
-		// Navigate up from .git/worktrees/<name> to get .git, then parent is main worktree
-		// gitDir = /path/to/main/.git/worktrees/<name>
-		mainGitDir := filepath.Dir(filepath.Dir(gitDir)) // .git
-		mainPath = filepath.Dir(mainGitDir)               // main worktree root
 	} else {
 		mainPath = currentPath
 	}
 
 	// Extract source/org/repo from main worktree path
-	homeDir, err := os.UserHomeDir()
+	srcDir, err := config.SrcRoot()
 	if err != nil {
-		return nil, fmt.Errorf("could not determine home directory: %w", err)
+		return nil, err
 	}
-	srcDir := filepath.Join(homeDir, "src")
 	relPath, err := filepath.Rel(srcDir, mainPath)
 	if err != nil || strings.HasPrefix(relPath, "..") {
 		return nil, fmt.Errorf("main worktree %s is not under %s", mainPath, srcDir)
 	}
 
-	parts := strings.Split(relPath, string(filepath.Separator))
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("unexpected repo path structure: %s", relPath)
+	source, org, repo, err := SplitRepoPath(relPath)
+	if err != nil {
+		return nil, err
 	}
 
 	return &RepoInfo{
 		MainPath:    mainPath,
-		Source:      parts[0],
-		Org:         parts[1],
-		Repo:        parts[2],
+		Source:      source,
+		Org:         org,
+		Repo:        repo,
 		CurrentPath: currentPath,
 		IsLinked:    isLinked,
 	}, nil
 }
 
+// MainPathFromGitFile returns the main worktree path of the linked worktree
+// at worktreePath by parsing its .git file.
+func MainPathFromGitFile(worktreePath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".git"))
+	if err != nil {
+		return "", fmt.Errorf("could not read .git file: %w", err)
+	}
+	// Format: "gitdir: /path/to/.git/worktrees/<name>"
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir: ") {
+		return "", fmt.Errorf("unexpected .git file format: %s", line)
+	}
+	gitDir := strings.TrimPrefix(line, "gitdir: ")
+	// Resolve relative paths
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	gitDir = filepath.Clean(gitDir)
+	// Navigate up from .git/worktrees/<name> to get .git, then parent is main worktree
+	// gitDir = /path/to/main/.git/worktrees/<name>
+	mainGitDir := filepath.Dir(filepath.Dir(gitDir)) // .git
+	return filepath.Dir(mainGitDir), nil             // main worktree root
+}
+
+// SplitRepoPath splits a repo path relative to the source root into its
+// source, org and repo components. Everything between the source and the
+// repo is the org, so nested groups are kept as a slash-separated org
+// (e.g. "gitlab.com/group/sub/project" → "gitlab.com", "group/sub", "project").
+func SplitRepoPath(relPath string) (source, org, repo string, err error) {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) < 3 {
+		return "", "", "", fmt.Errorf("unexpected repo path structure: %s", relPath)
+	}
+	return parts[0], strings.Join(parts[1:len(parts)-1], "/"), parts[len(parts)-1], nil
+}
+
 // ListWorktrees returns all worktrees for the given repo.
 func ListWorktrees(repoInfo *RepoInfo) ([]Worktree, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
@@ -166,36 +186,288 @@ func ParseWorktreeListOutput(output string, mainPath string) []Worktree {
 	return worktrees
 }
 
-// CreateWorktree creates a new worktree with a new branch.
-func CreateWorktree(repoInfo *RepoInfo, branchName string) (string, error) {
+// ListBranches returns the local branches of the repo plus remote branches
+// (with the remote prefix stripped) that have no local counterpart, sorted.
+func ListBranches(repoInfo *RepoInfo) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	cmd.Dir = repoInfo.MainPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+	return ParseBranchRefs(string(out)), nil
+}
+
+// ListRefs returns the short names of local branches and remote-tracking
+// branches (e.g. "main", "origin/feature-x"), excluding remote HEAD symrefs.
+func ListRefs(repoInfo *RepoInfo) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	cmd.Dir = repoInfo.MainPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+	var refs []string
+	for _, ref := range strings.Fields(string(out)) {
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		ref = strings.TrimPrefix(ref, "refs/heads/")
+		ref = strings.TrimPrefix(ref, "refs/remotes/")
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// ParseBranchRefs turns full ref names (refs/heads/x, refs/remotes/origin/x)
+// into unique branch names, dropping remote HEAD symrefs.
+func ParseBranchRefs(output string) []string {
+	seen := make(map[string]bool)
+	var branches []string
+	for _, ref := range strings.Fields(output) {
+		var name string
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			name = strings.TrimPrefix(ref, "refs/heads/")
+		case strings.HasPrefix(ref, "refs/remotes/"):
+			// refs/remotes/<remote>/<branch>
+			rest := strings.TrimPrefix(ref, "refs/remotes/")
+			i := strings.Index(rest, "/")
+			if i == -1 {
+				continue
+			}
+			name = rest[i+1:]
+			if name == "HEAD" {
+				continue
+			}
+		default:
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			branches = append(branches, name)
+		}
+	}
+	sort.Strings(branches)
+	return branches
+}
+
+// CreateOptions controls which branch a new worktree checks out.
+type CreateOptions struct {
+	From string // existing local or remote-tracking branch to check out (e.g. "origin/feature-x")
+	Base string // start point for a brand-new branch (defaults to the main worktree's HEAD)
+
+	SkipHooks            bool // don't run the post-wkt-new hook
+	IgnoreCommittedHooks bool // run only configured hook commands, even in a trusted repo
+}
+
+// CreateWorktree creates a new worktree for branchName and returns its path.
+// Files matching the configured worktree_files patterns are then copied or
+// symlinked from the main worktree (see CarryFiles) and, unless
+// opts.SkipHooks, the post-wkt-new hook runs in the new worktree.
+//
+// An existing local branch is checked out directly, a branch that only exists
+// on a remote is created as a tracking branch, and anything else becomes a new
+// branch starting at opts.Base. With opts.From, branchName may be empty and is
+// derived from the ref.
+func CreateWorktree(repoInfo *RepoInfo, branchName string, opts CreateOptions) (string, error) {
+	plan, err := planWorktreeAdd(repoInfo, branchName, opts)
+	if err != nil {
+		return "", err
+	}
+
 	root, err := GetWorktreeRoot()
 	if err != nil {
 		return "", err
 	}
 
-	targetPath := FormatWorktreePath(root, repoInfo.Source, repoInfo.Org, repoInfo.Repo, branchName)
+	targetPath := FormatWorktreePath(root, repoInfo.Source, repoInfo.Org, repoInfo.Repo, plan.branch)
 
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
 		return "", fmt.Errorf("could not create worktree parent directory: %w", err)
 	}
 
-	cmd := exec.Command("git", "worktree", "add", targetPath, "-b", branchName)
+	cmd := exec.Command("git", plan.args(targetPath)...)
 	cmd.Dir = repoInfo.MainPath
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("git worktree add failed: %w", err)
 	}
 
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	// Carry over untracked files such as .env; the worktree is usable without them
+	if err := CarryFiles(repoInfo.MainPath, targetPath, cfg.WorktreeFilesFor(repoInfo.Name())); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
+	if !opts.SkipHooks {
+		if err := runHook(cfg, hooks.PostWktNew, repoInfo, plan.branch, targetPath, targetPath, !opts.IgnoreCommittedHooks); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
 	return targetPath, nil
 }
 
-// RemoveWorktree removes a linked worktree, its local branch, and remote branch (best-effort).
+// addPlan describes a `git worktree add` invocation.
+type addPlan struct {
+	branch    string   // local branch the worktree will have checked out
+	flags     []string // e.g. -b <branch>, --track
+	commitish string   // optional start point or branch to check out
+}
+
+func (p addPlan) args(path string) []string {
+	args := append([]string{"worktree", "add"}, p.flags...)
+	args = append(args, path)
+	if p.commitish != "" {
+		args = append(args, p.commitish)
+	}
+	return args
+}
+
+func checkoutPlan(branch string) addPlan {
+	return addPlan{branch: branch, commitish: branch}
+}
+
+func trackPlan(branch, remoteRef string) addPlan {
+	return addPlan{branch: branch, flags: []string{"--track", "-b", branch}, commitish: remoteRef}
+}
+
+func newBranchPlan(branch, base string) addPlan {
+	return addPlan{branch: branch, flags: []string{"-b", branch}, commitish: base}
+}
+
+// planWorktreeAdd decides how the worktree's branch is obtained: checking out
+// an existing local branch, tracking a remote-only branch, or creating a new one.
+func planWorktreeAdd(repoInfo *RepoInfo, branchName string, opts CreateOptions) (addPlan, error) {
+	if opts.From != "" && opts.Base != "" {
+		return addPlan{}, fmt.Errorf("--from and --base cannot be used together")
+	}
+
+	if opts.From != "" {
+		if hasRef(repoInfo, "refs/heads/"+opts.From) {
+			if branchName != "" && branchName != opts.From {
+				return addPlan{}, fmt.Errorf("%q is an existing local branch; use --base to start a new branch %q from it", opts.From, branchName)
+			}
+			return checkoutPlan(opts.From), nil
+		}
+		if hasRef(repoInfo, "refs/remotes/"+opts.From) {
+			i := strings.Index(opts.From, "/")
+			if branchName == "" {
+				branchName = opts.From[i+1:]
+			}
+			if hasRef(repoInfo, "refs/heads/"+branchName) {
+				return addPlan{}, fmt.Errorf("local branch %q already exists", branchName)
+			}
+			return trackPlan(branchName, opts.From), nil
+		}
+		return addPlan{}, fmt.Errorf("%q is not a local or remote branch (run git fetch, or use --base to start from any ref)", opts.From)
+	}
+
+	if branchName == "" {
+		return addPlan{}, fmt.Errorf("a branch name is required")
+	}
+
+	if hasRef(repoInfo, "refs/heads/"+branchName) {
+		if opts.Base != "" {
+			return addPlan{}, fmt.Errorf("branch %q already exists; --base only applies to new branches", branchName)
+		}
+		return checkoutPlan(branchName), nil
+	}
+
+	remoteRef, err := findRemoteBranch(repoInfo, branchName)
+	if err != nil {
+		return addPlan{}, err
+	}
+	if remoteRef != "" {
+		if opts.Base != "" {
+			return addPlan{}, fmt.Errorf("branch %q already exists on %s; --base only applies to new branches", branchName, remoteRef)
+		}
+		return trackPlan(branchName, remoteRef), nil
+	}
+
+	return newBranchPlan(branchName, opts.Base), nil
+}
+
+// hasRef reports whether the fully qualified ref exists in the repo.
+func hasRef(repoInfo *RepoInfo, ref string) bool {
+	cmd := exec.Command("git", "show-ref", "--verify", "--quiet", ref)
+	cmd.Dir = repoInfo.MainPath
+	return cmd.Run() == nil
+}
+
+// findRemoteBranch returns the remote-tracking ref (e.g. "origin/feature-x")
+// for a branch that exists on a remote, preferring origin. It returns "" when
+// no remote has the branch and an error when several non-origin remotes do.
+func findRemoteBranch(repoInfo *RepoInfo, branch string) (string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/remotes/")
+	cmd.Dir = repoInfo.MainPath
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	var matches []string
+	for _, ref := range strings.Fields(string(out)) {
+		rest := strings.TrimPrefix(ref, "refs/remotes/")
+		i := strings.Index(rest, "/")
+		if i == -1 || rest[i+1:] != branch {
+			continue
+		}
+		if rest[:i] == "origin" {
+			return rest, nil
+		}
+		matches = append(matches, rest)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("branch %q exists on several remotes (%s); pick one with --from", branch, strings.Join(matches, ", "))
+}
+
+// RemoveWorktree removes a linked worktree and its local branch, and with
+// opts.DeleteRemote its branch on origin (best-effort). Unless opts.Force is
+// set, it refuses when CheckRemoval finds work that would be lost. The
+// pre-wkt-rm hook runs first and aborts the removal if it fails; post-wkt-rm
+// runs in the main worktree afterwards.
 // Returns a cdPath if the caller should change directory (e.g., when removing the current worktree).
-func RemoveWorktree(repoInfo *RepoInfo, wt Worktree) (string, error) {
+func RemoveWorktree(repoInfo *RepoInfo, wt Worktree, opts RemoveOptions) (string, error) {
 	if wt.IsMain {
 		return "", fmt.Errorf("cannot remove the main worktree")
 	}
 
+	if !opts.Force {
+		risks, err := CheckRemoval(repoInfo, wt)
+		if err != nil {
+			return "", err
+		}
+		if !risks.Empty() {
+			return "", fmt.Errorf("worktree %q has %s; use --force to remove it anyway", wt.Branch, strings.Join(risks.Summary(), ", "))
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	// The hook runs inside the worktree unless its directory is already gone
+	hookDir := wt.Path
+	if _, err := os.Stat(hookDir); err != nil {
+		hookDir = repoInfo.MainPath
+	}
+	if err := runHook(cfg, hooks.PreWktRm, repoInfo, wt.Branch, wt.Path, hookDir, true); err != nil {
+		return "", fmt.Errorf("%w; worktree not removed", err)
+	}
+
 	// If cwd is inside the worktree being removed, we need to cd elsewhere
 	var cdPath string
 	cwd, err := os.Getwd()
@@ -206,27 +478,40 @@ func RemoveWorktree(repoInfo *RepoInfo, wt Worktree) (string, error) {
 		}
 	}
 
-	// Remove worktree
-	cmd := exec.Command("git", "worktree", "remove", wt.Path, "--force")
+	// Remove worktree; without --force git itself refuses dirty worktrees
+	args := []string{"worktree", "remove", wt.Path}
+	if opts.Force {
+		args = append(args, "--force")
+	}
+	cmd := exec.Command("git", args...)
 	cmd.Dir = repoInfo.MainPath
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("git worktree remove failed: %w", err)
 	}
 
-	// Delete local branch (best-effort)
-	delBranch := exec.Command("git", "branch", "-D", wt.Branch)
-	delBranch.Dir = repoInfo.MainPath
-	_ = delBranch.Run()
-
-	// Delete remote branch (best-effort)
-	delRemote := exec.Command("git", "push", "origin", "--delete", wt.Branch)
-	delRemote.Dir = repoInfo.MainPath
-	_ = delRemote.Run()
+	if wt.Branch != "" {
+		// Delete local branch (best-effort); its commits were checked above
+		delBranch := exec.Command("git", "branch", "-D", wt.Branch)
+		delBranch.Dir = repoInfo.MainPath
+		_ = delBranch.Run()
+
+		if opts.DeleteRemote {
+			delRemote := exec.Command("git", "push", "origin", "--delete", wt.Branch)
+			delRemote.Dir = repoInfo.MainPath
+			if out, err := delRemote.CombinedOutput(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not delete remote branch %q: %s\n", wt.Branch, strings.TrimSpace(string(out)))
+			}
+		}
+	}
 
 	// Clean up empty parent directories in worktree root
 	cleanEmptyParents(wt.Path)
 
+	if err := runHook(cfg, hooks.PostWktRm, repoInfo, wt.Branch, wt.Path, repoInfo.MainPath, true); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+
 	return cdPath, nil
 }
 
@@ -247,6 +532,32 @@ func cleanEmptyParents(path string) {
 	}
 }
 
+// loadConfig loads the config file, treating a missing one as empty.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &config.Config{}, nil
+		}
+		return nil, fmt.Errorf("could not load config: %w", err)
+	}
+	return cfg, nil
+}
+
+// runHook runs a worktree hook, reading committed hooks from dir's .dev/hooks
+// if committed is set and the repo's are trusted.
+func runHook(cfg *config.Config, name string, repoInfo *RepoInfo, branch, path, dir string, committed bool) error {
+	env := map[string]string{
+		"DEV_HOOK":          name,
+		"DEV_REPO":          repoInfo.Name(),
+		"DEV_REPO_PATH":     repoInfo.MainPath,
+		"DEV_BRANCH":        branch,
+		"DEV_WORKTREE_PATH": path,
+	}
+	repo := repoInfo.Name()
+	return hooks.Run(name, dir, cfg.HookCommands(repo, name), committed && cfg.TrustsCommittedHooks(repo), env, os.Stderr)
+}
+
 // GetWorktreeRoot returns the worktree root directory from config or the default.
 func GetWorktreeRoot() (string, error) {
 	cfg, err := config.Load()
@@ -4,7 +4,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/dsaiztc/dev/internal/config"
 )
 
 func TestFormatWorktreePath(t *testing.T) {
@@ -44,6 +47,15 @@ func TestFormatWorktreePath(t *testing.T) {
 			branch: "main-v2",
 			want:   "/tmp/worktrees/gitlab.com/team/service__main-v2",
 		},
+		{
+			name:   "nested subgroup org",
+			root:   "/tmp/worktrees",
+			source: "gitlab.com",
+			org:    "group/sub",
+			repo:   "service",
+			branch: "feature-x",
+			want:   "/tmp/worktrees/gitlab.com/group/sub/service__feature-x",
+		},
 	}
 
 	for _, tt := range tests {
@@ -56,6 +68,40 @@ func TestFormatWorktreePath(t *testing.T) {
 	}
 }
 
+func TestSplitRepoPath(t *testing.T) {
+	tests := []struct {
+		relPath    string
+		wantSource string
+		wantOrg    string
+		wantRepo   string
+		wantErr    bool
+	}{
+		{relPath: "github.com/dsaiztc/dev", wantSource: "github.com", wantOrg: "dsaiztc", wantRepo: "dev"},
+		{relPath: "gitlab.com/group/sub/project", wantSource: "gitlab.com", wantOrg: "group/sub", wantRepo: "project"},
+		{relPath: "gitlab.com/a/b/c/project", wantSource: "gitlab.com", wantOrg: "a/b/c", wantRepo: "project"},
+		{relPath: "github.com/solo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relPath, func(t *testing.T) {
+			source, org, repo, err := SplitRepoPath(tt.relPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitRepoPath: %v", err)
+			}
+			if source != tt.wantSource || org != tt.wantOrg || repo != tt.wantRepo {
+				t.Errorf("SplitRepoPath(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.relPath, source, org, repo, tt.wantSource, tt.wantOrg, tt.wantRepo)
+			}
+		})
+	}
+}
+
 func TestParseWorktreeListOutput(t *testing.T) {
 	mainPath := "/home/user/src/github.com/dsaiztc/dev"
 
@@ -236,3 +282,237 @@ func TestCreateAndListWorktrees_Integration(t *testing.T) {
 		t.Errorf("expected second worktree branch 'feature-a', got %q", worktrees[1].Branch)
 	}
 }
+
+func TestParseBranchRefs(t *testing.T) {
+	output := "refs/heads/main\n" +
+		"refs/heads/feature/login\n" +
+		"refs/remotes/origin/HEAD\n" +
+		"refs/remotes/origin/main\n" +
+		"refs/remotes/origin/feature-x\n" +
+		"refs/remotes/upstream/release/1.0\n"
+
+	got := ParseBranchRefs(output)
+	want := []string{"feature-x", "feature/login", "main", "release/1.0"}
+	if len(got) != len(want) {
+		t.Fatalf("ParseBranchRefs() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseBranchRefs()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestListBranches_Integration(t *testing.T) {
+	_, repoPath := setupTestRepo(t)
+	if err := exec.Command("git", "-C", repoPath, "branch", "feature-b").Run(); err != nil {
+		t.Fatalf("git branch: %v", err)
+	}
+
+	branches, err := ListBranches(&RepoInfo{MainPath: repoPath})
+	if err != nil {
+		t.Fatalf("ListBranches: %v", err)
+	}
+	found := false
+	for _, b := range branches {
+		if b == "feature-b" {
+			found = true
+		}
+	}
+	if !found || len(branches) != 2 {
+		t.Errorf("ListBranches() = %v, want the default branch and feature-b", branches)
+	}
+}
+
+// setupClonedRepo clones a fresh upstream repo (which has the extra branches
+// given) into ~/src/github.com/testuser/clone and points HOME at the temp home
+// so CreateWorktree uses the default worktree root.
+func setupClonedRepo(t *testing.T, upstreamBranches ...string) (*RepoInfo, string) {
+	t.Helper()
+	homeDir, upstream := setupTestRepo(t)
+	t.Setenv("HOME", homeDir)
+	t.Setenv("DEV_SRC_ROOT", "")
+
+	for _, b := range upstreamBranches {
+		if err := exec.Command("git", "-C", upstream, "branch", b).Run(); err != nil {
+			t.Fatalf("git branch %s: %v", b, err)
+		}
+	}
+
+	clonePath := filepath.Join(homeDir, "src", "github.com", "testuser", "clone")
+	if out, err := exec.Command("git", "clone", "-q", upstream, clonePath).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+
+	info := &RepoInfo{
+		MainPath: clonePath,
+		Source:   "github.com",
+		Org:      "testuser",
+		Repo:     "clone",
+	}
+	return info, filepath.Join(homeDir, "src__worktrees")
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCreateWorktree_NewBranch(t *testing.T) {
+	info, wtRoot := setupClonedRepo(t)
+
+	path, err := CreateWorktree(info, "feature-new", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if want := FormatWorktreePath(wtRoot, "github.com", "testuser", "clone", "feature-new"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if got := gitOutput(t, path, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature-new" {
+		t.Errorf("checked out %q, want feature-new", got)
+	}
+}
+
+func TestCreateWorktree_NewBranchWithBase(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	base := gitOutput(t, info.MainPath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(info.MainPath, "later.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitOutput(t, info.MainPath, "-c", "user.email=t@t", "-c", "user.name=T", "commit", "-qam", "later", "--allow-empty")
+
+	path, err := CreateWorktree(info, "from-base", CreateOptions{Base: base})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if got := gitOutput(t, path, "rev-parse", "HEAD"); got != base {
+		t.Errorf("HEAD = %s, want base %s", got, base)
+	}
+}
+
+func TestCreateWorktree_ExistingLocalBranch(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	gitOutput(t, info.MainPath, "branch", "local-only")
+
+	path, err := CreateWorktree(info, "local-only", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if got := gitOutput(t, path, "rev-parse", "--abbrev-ref", "HEAD"); got != "local-only" {
+		t.Errorf("checked out %q, want local-only", got)
+	}
+
+	if _, err := CreateWorktree(info, "local-only", CreateOptions{Base: "HEAD"}); err == nil {
+		t.Error("expected error for --base with an existing branch")
+	}
+}
+
+func TestCreateWorktree_RemoteOnlyBranch(t *testing.T) {
+	info, _ := setupClonedRepo(t, "feature-x")
+
+	path, err := CreateWorktree(info, "feature-x", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if got := gitOutput(t, path, "rev-parse", "--abbrev-ref", "feature-x@{upstream}"); got != "origin/feature-x" {
+		t.Errorf("upstream = %q, want origin/feature-x", got)
+	}
+}
+
+func TestCreateWorktree_From(t *testing.T) {
+	info, wtRoot := setupClonedRepo(t, "feature-y")
+
+	path, err := CreateWorktree(info, "", CreateOptions{From: "origin/feature-y"})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if want := FormatWorktreePath(wtRoot, "github.com", "testuser", "clone", "feature-y"); path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if got := gitOutput(t, path, "rev-parse", "--abbrev-ref", "feature-y@{upstream}"); got != "origin/feature-y" {
+		t.Errorf("upstream = %q, want origin/feature-y", got)
+	}
+
+	if _, err := CreateWorktree(info, "", CreateOptions{From: "origin/does-not-exist"}); err == nil {
+		t.Error("expected error for unknown --from ref")
+	}
+}
+
+func TestWorktreeHooks(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	logPath := filepath.Join(t.TempDir(), "hooks.log")
+	cfg := &config.Config{
+		Hooks: config.Hooks{
+			"post-wkt-new": {`echo "new $DEV_REPO $DEV_BRANCH $DEV_WORKTREE_PATH" >> ` + logPath},
+			"pre-wkt-rm":   {`test ! -e "$DEV_WORKTREE_PATH/keep"`},
+			"post-wkt-rm":  {`echo "rm $DEV_BRANCH" >> ` + logPath},
+		},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+
+	path, err := CreateWorktree(info, "hooked", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	wt := Worktree{Path: path, Branch: "hooked"}
+
+	// A failing pre-wkt-rm hook aborts the removal
+	writeFile(t, filepath.Join(path, "keep"), "", 0o644)
+	if _, err := RemoveWorktree(info, wt, RemoveOptions{Force: true}); err == nil {
+		t.Fatal("expected RemoveWorktree to fail when pre-wkt-rm fails")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("worktree removed despite failing pre-wkt-rm hook: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(path, "keep")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RemoveWorktree(info, wt, RemoveOptions{}); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "new github.com/testuser/clone hooked " + path + "\nrm hooked\n"
+	if string(data) != want {
+		t.Errorf("hook log = %q, want %q", data, want)
+	}
+}
+
+func TestWorktreeHooks_CommittedScriptNeedsTrust(t *testing.T) {
+	info, _ := setupClonedRepo(t)
+	writeFile(t, filepath.Join(info.MainPath, ".dev", "hooks", "post-wkt-new"), "#!/bin/sh\ntouch ran\n", 0o755)
+	gitOutput(t, info.MainPath, "add", ".dev")
+	gitOutput(t, info.MainPath, "-c", "user.name=T", "-c", "user.email=t@example.com", "commit", "-q", "-m", "add hook")
+
+	path, err := CreateWorktree(info, "untrusted", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "ran")); !os.IsNotExist(err) {
+		t.Error("committed hook ran without trust_committed_hooks")
+	}
+
+	cfg := &config.Config{Repos: map[string]config.RepoConfig{info.Name(): {TrustCommittedHooks: true}}}
+	if err := config.Save(cfg); err != nil {
+		t.Fatalf("config.Save: %v", err)
+	}
+	path, err = CreateWorktree(info, "trusted", CreateOptions{})
+	if err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "ran")); err != nil {
+		t.Errorf("committed hook did not run in a trusted repo: %v", err)
+	}
+}